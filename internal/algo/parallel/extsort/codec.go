@@ -0,0 +1,31 @@
+package extsort
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// Codec writes and reads the elements of the temporary files.
+type Codec[T any] interface {
+	// NewEncoder returns a function writing the elements to w one by one.
+	NewEncoder(w io.Writer) func(*T) error
+	// NewDecoder returns a function reading the elements written by the encoder from r one by one.
+	NewDecoder(r io.Reader) func(*T) error
+}
+
+// Gob is a Codec made with encoding/gob.
+type Gob[T any] struct{}
+
+func (Gob[T]) NewEncoder(w io.Writer) func(*T) error {
+	enc := gob.NewEncoder(w)
+	return func(x *T) error {
+		return enc.Encode(x)
+	}
+}
+
+func (Gob[T]) NewDecoder(r io.Reader) func(*T) error {
+	dec := gob.NewDecoder(r)
+	return func(x *T) error {
+		return dec.Decode(x)
+	}
+}
@@ -0,0 +1,404 @@
+package extsort
+
+import (
+	"container/heap"
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel"
+	"github.com/koss-null/funcfrog/internal/algo/parallel/qsort"
+)
+
+const (
+	// DefaultRunSize is the amount of elements sorted in memory at once if Options.RunSize is not set.
+	DefaultRunSize = 1 << 20
+	// maxFanIn is the maximal amount of runs merged at once.
+	maxFanIn = 1 << 6
+	// cacheBlocks is the amount of decoded blocks of the result kept in memory.
+	cacheBlocks = 1 << 6
+)
+
+// Options sets up an external sort.
+type Options[T any] struct {
+	// Dir is the directory for the temporary files, the default directory for temporary files if empty.
+	Dir string
+	// RunSize is the maximal amount of elements kept in memory to be sorted at once, DefaultRunSize if not positive.
+	RunSize int
+	// Codec writes the elements to the temporary files, Gob if nil.
+	Codec Codec[T]
+	// Spawn starts the parallel merges, parallel.Go if nil.
+	Spawn parallel.Spawn
+}
+
+// Sorter sorts the elements added to it writing them into temporary files by sorted runs of opts.RunSize.
+type Sorter[T any] struct {
+	less    func(*T, *T) bool
+	threads int
+	dir     string
+	size    int
+	codec   Codec[T]
+	spawn   parallel.Spawn
+
+	buf  []T
+	runs []*run[T]
+}
+
+// New creates a Sorter making its temporary directory.
+func New[T any](less func(*T, *T) bool, threads int, opts Options[T]) (*Sorter[T], error) {
+	dir, err := os.MkdirTemp(opts.Dir, "funcfrog-sort-*")
+	if err != nil {
+		return nil, err
+	}
+	s := &Sorter[T]{
+		less:    less,
+		threads: max(threads, 1),
+		dir:     dir,
+		size:    opts.RunSize,
+		codec:   opts.Codec,
+		spawn:   opts.Spawn,
+	}
+	if s.size <= 0 {
+		s.size = DefaultRunSize
+	}
+	if s.codec == nil {
+		s.codec = Gob[T]{}
+	}
+	if s.spawn == nil {
+		s.spawn = parallel.Go
+	}
+	return s, nil
+}
+
+// Add adds the elements to the sorter, a sorted run is written each time opts.RunSize elements are added.
+func (s *Sorter[T]) Add(data []T) error {
+	for len(data) != 0 {
+		if s.buf == nil {
+			s.buf = make([]T, 0, min(s.size, max(len(data), blockSize)))
+		}
+		n := min(len(data), s.size-len(s.buf))
+		s.buf = append(s.buf, data[:n]...)
+		data = data[n:]
+		if len(s.buf) == s.size {
+			if err := s.spill(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sorter[T]) spill() error {
+	if len(s.buf) == 0 {
+		return nil
+	}
+	data := qsort.Sort(s.buf, s.less, s.threads)
+	r, err := writeRun(s.dir, s.codec, data)
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, r)
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// Abort removes the temporary files.
+func (s *Sorter[T]) Abort() {
+	os.RemoveAll(s.dir)
+}
+
+// Sort writes the last run and merges all of them.
+// The runs are merged by groups of maxFanIn in parallel until there are maxFanIn of them at most;
+// the last ones are split into s.threads ranges of values merged in parallel.
+// The result is read lazily from the merged files.
+func (s *Sorter[T]) Sort() (*Sorted[T], error) {
+	err := s.spill()
+	s.buf = nil
+	for err == nil && len(s.runs) > maxFanIn {
+		err = s.mergeGroups()
+	}
+	if err == nil && len(s.runs) > 1 {
+		err = s.mergeRanges()
+	}
+	if err != nil {
+		s.Abort()
+		return nil, err
+	}
+	return openSorted(s.dir, s.runs, s.codec)
+}
+
+// mergeGroups merges the runs by groups of maxFanIn in s.threads parts of the groups in parallel.
+func (s *Sorter[T]) mergeGroups() error {
+	groups := (len(s.runs) + maxFanIn - 1) / maxFanIn
+	merged := make([]*run[T], groups)
+	errs := make([]error, groups)
+	parallel.Parts(s.spawn, groups, s.threads, func(lf, rg int) {
+		for g := lf; g < rg; g++ {
+			group := s.runs[g*maxFanIn : min((g+1)*maxFanIn, len(s.runs))]
+			merged[g], errs[g] = s.merge(group, nil, nil)
+		}
+	})
+
+	if err := firstErr(errs); err != nil {
+		return err
+	}
+	s.remove(s.runs)
+	s.runs = merged
+	return nil
+}
+
+// mergeRanges merges all the runs into s.threads runs holding consecutive ranges of values.
+func (s *Sorter[T]) mergeRanges() error {
+	bounds := s.splitters()
+	merged := make([]*run[T], len(bounds)+1)
+	errs := make([]error, len(merged))
+	parallel.Parts(s.spawn, len(merged), len(merged), func(i, _ int) {
+		var lo, hi *T
+		if i > 0 {
+			lo = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			hi = &bounds[i]
+		}
+		merged[i], errs[i] = s.merge(s.runs, lo, hi)
+	})
+
+	if err := firstErr(errs); err != nil {
+		return err
+	}
+	s.remove(s.runs)
+	s.runs = merged
+	return nil
+}
+
+// splitters picks the values splitting the runs into s.threads ranges of about the same size
+// out of the first elements of their blocks.
+func (s *Sorter[T]) splitters() []T {
+	var heads []T
+	for _, r := range s.runs {
+		for i := range r.blocks {
+			heads = append(heads, r.blocks[i].head)
+		}
+	}
+	sort.Slice(heads, func(i, j int) bool { return s.less(&heads[i], &heads[j]) })
+
+	parts := min(s.threads, len(heads))
+	bounds := make([]T, 0, parts)
+	for i := 1; i < parts; i++ {
+		b := heads[i*len(heads)/parts]
+		if len(bounds) == 0 || s.less(&bounds[len(bounds)-1], &b) {
+			bounds = append(bounds, b)
+		}
+	}
+	return bounds
+}
+
+func (s *Sorter[T]) remove(runs []*run[T]) {
+	for _, r := range runs {
+		os.Remove(r.path)
+	}
+}
+
+// merge merges the elements x of the runs with lo <= x < hi into a new run, nil bounds are not checked.
+func (s *Sorter[T]) merge(runs []*run[T], lo, hi *T) (*run[T], error) {
+	w, err := newRunWriter(s.dir, s.codec)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &mergeHeap[T]{less: s.less}
+	defer func() {
+		for _, it := range h.items {
+			it.rr.close()
+		}
+	}()
+	for _, r := range runs {
+		from := 0
+		if lo != nil {
+			from = r.search(lo, s.less)
+		}
+		rr, err := openRun(r, s.codec, from)
+		if err != nil {
+			w.close()
+			return nil, err
+		}
+		it := &mergeItem[T]{rr: rr}
+		for {
+			if it.x, err = rr.read(); err != nil || it.x == nil || lo == nil || !s.less(it.x, lo) {
+				break
+			}
+		}
+		if err != nil || it.x == nil || (hi != nil && !s.less(it.x, hi)) {
+			rr.close()
+			if err != nil {
+				w.close()
+				return nil, err
+			}
+			continue
+		}
+		h.items = append(h.items, it)
+	}
+	heap.Init(h)
+
+	for len(h.items) != 0 {
+		it := h.items[0]
+		if err := w.write(it.x); err != nil {
+			w.close()
+			return nil, err
+		}
+		if it.x, err = it.rr.read(); err != nil {
+			w.close()
+			return nil, err
+		}
+		if it.x == nil || (hi != nil && !s.less(it.x, hi)) {
+			it.rr.close()
+			heap.Pop(h)
+			continue
+		}
+		heap.Fix(h, 0)
+	}
+	return w.close()
+}
+
+type mergeItem[T any] struct {
+	rr *runReader[T]
+	x  *T
+}
+
+// mergeHeap keeps the readers ordered by their current elements.
+type mergeHeap[T any] struct {
+	items []*mergeItem[T]
+	less  func(*T, *T) bool
+}
+
+func (h *mergeHeap[T]) Len() int           { return len(h.items) }
+func (h *mergeHeap[T]) Less(i, j int) bool { return h.less(h.items[i].x, h.items[j].x) }
+func (h *mergeHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *mergeHeap[T]) Push(x any)         { h.items = append(h.items, x.(*mergeItem[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	it := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return it
+}
+
+// Sorted is the result of an external sort read from its temporary files block by block.
+// The files are removed by Close.
+type Sorted[T any] struct {
+	dir    string
+	codec  Codec[T]
+	files  []*os.File
+	runs   []*run[T]
+	starts []int
+	cnt    int
+
+	mx    sync.Mutex
+	cache map[blockKey][]T
+	order []blockKey
+}
+
+type blockKey struct {
+	run, block int
+}
+
+func openSorted[T any](dir string, runs []*run[T], codec Codec[T]) (*Sorted[T], error) {
+	s := &Sorted[T]{
+		dir:   dir,
+		codec: codec,
+		cache: make(map[blockKey][]T, cacheBlocks),
+	}
+	for _, r := range runs {
+		if r.cnt == 0 {
+			os.Remove(r.path)
+			continue
+		}
+		f, err := os.Open(r.path)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.files = append(s.files, f)
+		s.runs = append(s.runs, r)
+		s.starts = append(s.starts, s.cnt)
+		s.cnt += r.cnt
+	}
+	return s, nil
+}
+
+// Len returns the amount of the sorted elements.
+func (s *Sorted[T]) Len() int {
+	return s.cnt
+}
+
+// Get returns the element i of the sorted ones.
+func (s *Sorted[T]) Get(i int) (*T, error) {
+	r := sort.Search(len(s.starts), func(r int) bool { return s.starts[r] > i }) - 1
+	i -= s.starts[r]
+	// all the blocks of a run but the last one are full
+	b := i / blockSize
+	key := blockKey{run: r, block: b}
+
+	s.mx.Lock()
+	data, ok := s.cache[key]
+	s.mx.Unlock()
+	if !ok {
+		var err error
+		if data, err = decodeBlock(s.files[r], &s.runs[r].blocks[b], s.codec); err != nil {
+			return nil, err
+		}
+		s.store(key, data)
+	}
+	return &data[i-b*blockSize], nil
+}
+
+func (s *Sorted[T]) store(key blockKey, data []T) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.cache[key]; ok {
+		return
+	}
+	if len(s.order) == cacheBlocks {
+		delete(s.cache, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.cache[key] = data
+	s.order = append(s.order, key)
+}
+
+// Close closes and removes the temporary files, it returns the first error of doing it.
+func (s *Sorted[T]) Close() error {
+	var errs []error
+	for _, f := range s.files {
+		errs = append(errs, f.Close())
+	}
+	s.files = nil
+	errs = append(errs, os.RemoveAll(s.dir))
+	return firstErr(errs)
+}
+
+// firstErr returns the first of errs which is not nil.
+func firstErr(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func min[T constraints.Ordered](a, b T) T {
+	if a > b {
+		return b
+	}
+	return a
+}
+
+func max[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,143 @@
+package extsort
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func less(a, b *int) bool { return *a < *b }
+
+func sortAll(t *testing.T, data []int, threads int, opts Options[int]) *Sorted[int] {
+	s, err := New(less, threads, opts)
+	require.NoError(t, err)
+	// added by the parts of a different size than a run
+	for len(data) != 0 {
+		n := min(len(data), 777)
+		require.NoError(t, s.Add(data[:n]))
+		data = data[n:]
+	}
+	res, err := s.Sort()
+	require.NoError(t, err)
+	return res
+}
+
+func requireSorted(t *testing.T, exp []int, res *Sorted[int]) {
+	sort.Ints(exp)
+	require.Equal(t, len(exp), res.Len())
+	for i := range exp {
+		x, err := res.Get(i)
+		require.NoError(t, err)
+		require.Equal(t, exp[i], *x)
+	}
+}
+
+func Test_Sort(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+	data := make([]int, 50_000)
+	for i := range data {
+		data[i] = rnd.Intn(10_000)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		threads int
+		runSize int
+	}{
+		{"one run", 4, 100_000},
+		{"single thread", 1, 3000},
+		{"ranges", 7, 3000},
+		// more runs than maxFanIn are merged by groups first
+		{"groups", 4, 500},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			in := append([]int(nil), data...)
+			res := sortAll(t, in, tc.threads, Options[int]{Dir: t.TempDir(), RunSize: tc.runSize})
+			defer res.Close()
+			requireSorted(t, append([]int(nil), data...), res)
+		})
+	}
+}
+
+func Test_Sort_equal(t *testing.T) {
+	t.Parallel()
+
+	data := make([]int, 10_000)
+	for i := range data {
+		data[i] = i % 3
+	}
+	res := sortAll(t, data, 8, Options[int]{Dir: t.TempDir(), RunSize: 1000})
+	defer res.Close()
+	requireSorted(t, append([]int(nil), data...), res)
+}
+
+func Test_Sort_empty(t *testing.T) {
+	t.Parallel()
+
+	res := sortAll(t, nil, 4, Options[int]{Dir: t.TempDir()})
+	defer res.Close()
+	require.Zero(t, res.Len())
+}
+
+// binCodec writes ints as fixed size binary values.
+type binCodec struct{}
+
+func (binCodec) NewEncoder(w io.Writer) func(*int) error {
+	return func(x *int) error {
+		return binary.Write(w, binary.LittleEndian, int64(*x))
+	}
+}
+
+func (binCodec) NewDecoder(r io.Reader) func(*int) error {
+	return func(x *int) error {
+		var v int64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		*x = int(v)
+		return err
+	}
+}
+
+func Test_Sort_codec(t *testing.T) {
+	t.Parallel()
+
+	data := make([]int, 10_000)
+	for i := range data {
+		data[i] = len(data) - i
+	}
+	res := sortAll(t, data, 3, Options[int]{Dir: t.TempDir(), RunSize: 1500, Codec: binCodec{}})
+	defer res.Close()
+	requireSorted(t, append([]int(nil), data...), res)
+}
+
+func Test_Sorted_Close(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	res := sortAll(t, []int{3, 1, 2}, 2, Options[int]{Dir: dir, RunSize: 2})
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, res.Close())
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func Test_New_error(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(less, 1, Options[int]{Dir: "/not/existing/dir"})
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -0,0 +1,173 @@
+package extsort
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+)
+
+// blockSize is the amount of elements encoded together, each block is decoded on its own.
+const blockSize = 1 << 10
+
+type block[T any] struct {
+	off, size int64
+	cnt       int
+	// head is the first element of the block
+	head T
+}
+
+// run is a sorted temporary file made of blocks.
+type run[T any] struct {
+	path   string
+	blocks []block[T]
+	cnt    int
+}
+
+// search returns the index of the first block which may hold the elements not less than x.
+func (r *run[T]) search(x *T, less func(*T, *T) bool) int {
+	i := sort.Search(len(r.blocks), func(i int) bool {
+		return !less(&r.blocks[i].head, x)
+	})
+	return max(i-1, 0)
+}
+
+// countWriter counts the bytes written to the file.
+type countWriter struct {
+	w   io.Writer
+	cnt int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.cnt += int64(n)
+	return n, err
+}
+
+// runWriter writes the sorted elements into a new run block by block.
+type runWriter[T any] struct {
+	codec Codec[T]
+	f     *os.File
+	bw    *bufio.Writer
+	cw    *countWriter
+	enc   func(*T) error
+	run   *run[T]
+}
+
+func newRunWriter[T any](dir string, codec Codec[T]) (*runWriter[T], error) {
+	f, err := os.CreateTemp(dir, "run-*")
+	if err != nil {
+		return nil, err
+	}
+	w := &runWriter[T]{
+		codec: codec,
+		f:     f,
+		bw:    bufio.NewWriter(f),
+		run:   &run[T]{path: f.Name()},
+	}
+	w.cw = &countWriter{w: w.bw}
+	return w, nil
+}
+
+func (w *runWriter[T]) write(x *T) error {
+	blocks := w.run.blocks
+	if len(blocks) == 0 || blocks[len(blocks)-1].cnt == blockSize {
+		w.finishBlock()
+		// each block has its own encoder, so it's decoded without the previous ones
+		w.enc = w.codec.NewEncoder(w.cw)
+		w.run.blocks = append(w.run.blocks, block[T]{off: w.cw.cnt, head: *x})
+	}
+	if err := w.enc(x); err != nil {
+		return err
+	}
+	w.run.blocks[len(w.run.blocks)-1].cnt++
+	w.run.cnt++
+	return nil
+}
+
+func (w *runWriter[T]) finishBlock() {
+	if len(w.run.blocks) != 0 {
+		last := &w.run.blocks[len(w.run.blocks)-1]
+		last.size = w.cw.cnt - last.off
+	}
+}
+
+// close finishes the run and returns it.
+func (w *runWriter[T]) close() (*run[T], error) {
+	w.finishBlock()
+	err := w.bw.Flush()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(w.run.path)
+		return nil, err
+	}
+	return w.run, nil
+}
+
+// writeRun writes the sorted data into a new run.
+func writeRun[T any](dir string, codec Codec[T], data []T) (*run[T], error) {
+	w, err := newRunWriter(dir, codec)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		if err := w.write(&data[i]); err != nil {
+			w.close()
+			return nil, err
+		}
+	}
+	return w.close()
+}
+
+// decodeBlock reads the block b of the file f.
+func decodeBlock[T any](f io.ReaderAt, b *block[T], codec Codec[T]) ([]T, error) {
+	dec := codec.NewDecoder(bufio.NewReader(io.NewSectionReader(f, b.off, b.size)))
+	res := make([]T, b.cnt)
+	for i := range res {
+		if err := dec(&res[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// runReader reads the elements of a run one by one starting with a block.
+type runReader[T any] struct {
+	r     *run[T]
+	f     *os.File
+	codec Codec[T]
+	next  int
+	buf   []T
+	pos   int
+}
+
+func openRun[T any](r *run[T], codec Codec[T], fromBlock int) (*runReader[T], error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	return &runReader[T]{r: r, f: f, codec: codec, next: fromBlock}, nil
+}
+
+// read returns the next element, it returns nil after the last one.
+func (rr *runReader[T]) read() (*T, error) {
+	for rr.pos == len(rr.buf) {
+		if rr.next == len(rr.r.blocks) {
+			return nil, nil
+		}
+		buf, err := decodeBlock(rr.f, &rr.r.blocks[rr.next], rr.codec)
+		if err != nil {
+			return nil, err
+		}
+		rr.buf, rr.pos = buf, 0
+		rr.next++
+	}
+	rr.pos++
+	return &rr.buf[rr.pos-1], nil
+}
+
+func (rr *runReader[T]) close() {
+	rr.f.Close()
+}
@@ -0,0 +1,243 @@
+package radix
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel"
+)
+
+const (
+	// singleThreadTreshold is the amount of elements sorted in a single goroutine.
+	singleThreadTreshold = 1 << 14
+	// smallTreshold is the amount of elements sorted with a comparison sort.
+	smallTreshold = 1 << 6
+	// digitBits is the size of a digit of an LSD radix sort, 64 bit keys are sorted in 6 passes.
+	digitBits = 11
+	digits    = 1 << digitBits
+	// byteValues is the amount of the values of a byte of an MSD radix sort.
+	byteValues = 1 << 8
+)
+
+type uintItem struct {
+	key uint64
+	idx int
+}
+
+// Sort sorts data by the keys with a parallel LSD radix sort of 11 bit digits.
+// The keys are evaluated once for each element. The sort is stable.
+// The parts sorted in parallel are started with spawn.
+func Sort[T any](data []T, key func(*T) uint64, threads int, spawn parallel.Spawn) []T {
+	threads = max(min(threads, len(data)/singleThreadTreshold), 1)
+
+	items := make([]uintItem, len(data))
+	parallel.Parts(spawn, len(items), threads, func(lf, rg int) {
+		for i := lf; i < rg; i++ {
+			items[i] = uintItem{key: key(&data[i]), idx: i}
+		}
+	})
+
+	if len(items) <= smallTreshold {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].key < items[j].key })
+	} else {
+		items = lsd(items, threads, spawn)
+	}
+	return gather(data, len(items), threads, spawn, func(i int) int { return items[i].idx })
+}
+
+// lsd sorts the items digit by digit starting with the lowest one.
+// Each part of the items counts its digits and scatters them in parallel, the digits equal for all items are skipped.
+func lsd(items []uintItem, threads int, spawn parallel.Spawn) []uintItem {
+	aux := make([]uintItem, len(items))
+	step := (len(items) + threads - 1) / threads
+	counts := make([][digits]int, threads)
+	for shift := 0; shift < 64; shift += digitBits {
+		parallel.Parts(spawn, len(items), threads, func(lf, rg int) {
+			cnt := &counts[lf/step]
+			*cnt = [digits]int{}
+			for i := lf; i < rg; i++ {
+				cnt[items[i].key>>shift&(digits-1)]++
+			}
+		})
+
+		// offsets of each digit of each part: all the lower digits first, then the same digit of the previous parts
+		skip := false
+		off := 0
+		for d := 0; d < digits; d++ {
+			total := 0
+			for p := range counts {
+				c := counts[p][d]
+				counts[p][d] = off + total
+				total += c
+			}
+			if total == len(items) {
+				skip = true
+				break
+			}
+			off += total
+		}
+		if skip {
+			continue
+		}
+
+		parallel.Parts(spawn, len(items), threads, func(lf, rg int) {
+			pos := &counts[lf/step]
+			for i := lf; i < rg; i++ {
+				d := items[i].key >> shift & (digits - 1)
+				aux[pos[d]] = items[i]
+				pos[d]++
+			}
+		})
+		items, aux = aux, items
+	}
+	return items
+}
+
+type strItem struct {
+	key string
+	idx int
+}
+
+// SortStrings sorts data by the string keys with an MSD radix sort.
+// The buckets of the first bytes are sorted in parallel started with spawn.
+// The keys are evaluated once for each element. The sort is stable.
+func SortStrings[T any](data []T, key func(*T) string, threads int, spawn parallel.Spawn) []T {
+	threads = max(min(threads, len(data)/singleThreadTreshold), 1)
+
+	items := make([]strItem, len(data))
+	parallel.Parts(spawn, len(items), threads, func(lf, rg int) {
+		for i := lf; i < rg; i++ {
+			items[i] = strItem{key: key(&data[i]), idx: i}
+		}
+	})
+
+	m := &msdSort{spawn: spawn, tickets: make(chan struct{}, threads-1)}
+	m.sort(items, make([]strItem, len(items)), 0)
+	return gather(data, len(items), threads, spawn, func(i int) int { return items[i].idx })
+}
+
+// byteAt returns the byte d of s plus one, or 0 if s is shorter.
+func byteAt(s string, d int) int {
+	if d < len(s) {
+		return int(s[d]) + 1
+	}
+	return 0
+}
+
+// msdSort is an MSD radix sort spawning the sorts of the buckets while there are free tickets.
+type msdSort struct {
+	spawn   parallel.Spawn
+	tickets chan struct{}
+}
+
+// sort sorts the items with equal first d bytes of the keys by the rest of them.
+// The buckets are spawned while there is a free ticket, the spawned ones are waited for before it returns.
+func (m *msdSort) sort(items, aux []strItem, d int) {
+	if len(items) <= smallTreshold {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].key[d:] < items[j].key[d:] })
+		return
+	}
+
+	// start[b] is the first position of the bucket b after the prefix sums
+	var start [byteValues + 2]int
+	for {
+		for i := range items {
+			start[byteAt(items[i].key, d)+1]++
+		}
+		first := byteAt(items[0].key, d)
+		if start[first+1] != len(items) {
+			break
+		}
+		if first == 0 {
+			// all the keys are equal
+			return
+		}
+		// all the keys have the same byte d
+		start[first+1] = 0
+		d++
+	}
+	for b := 1; b < len(start); b++ {
+		start[b] += start[b-1]
+	}
+	pos := start
+	for i := range items {
+		b := byteAt(items[i].key, d)
+		aux[pos[b]] = items[i]
+		pos[b]++
+	}
+	copy(items, aux)
+
+	// the keys ending at d are all equal
+	var waits []func()
+	for b := 1; b <= byteValues; b++ {
+		lf, rg := start[b], start[b+1]
+		if rg-lf < 2 {
+			continue
+		}
+		if rg-lf > singleThreadTreshold {
+			select {
+			case m.tickets <- struct{}{}:
+				waits = append(waits, m.spawn(func() {
+					defer func() { <-m.tickets }()
+					m.sort(items[lf:rg], aux[lf:rg], d+1)
+				}))
+				continue
+			default:
+			}
+		}
+		m.sort(items[lf:rg], aux[lf:rg], d+1)
+	}
+	for _, wait := range waits {
+		wait()
+	}
+}
+
+// gather reorders data taking the element idx(i) to the position i.
+func gather[T any](data []T, n, threads int, spawn parallel.Spawn, idx func(int) int) []T {
+	res := make([]T, n)
+	parallel.Parts(spawn, n, threads, func(lf, rg int) {
+		for i := lf; i < rg; i++ {
+			res[i] = data[idx(i)]
+		}
+	})
+	copy(data, res)
+	return data
+}
+
+// IntKey maps an integer to a key of the same order.
+func IntKey[K constraints.Integer](k K) uint64 {
+	if K(0)-1 < 0 {
+		// the sign bit is flipped, so the negative numbers go first
+		return uint64(int64(k)) ^ 1<<63
+	}
+	return uint64(k)
+}
+
+// FloatKey maps a float to a key of the same order, NaNs go last.
+func FloatKey[K constraints.Float](k K) uint64 {
+	f := float64(k)
+	if math.IsNaN(f) {
+		return math.MaxUint64
+	}
+	bits := math.Float64bits(f)
+	if bits>>63 == 1 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+func min[T constraints.Ordered](a, b T) T {
+	if a > b {
+		return b
+	}
+	return a
+}
+
+func max[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,134 @@
+package radix
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel"
+	"github.com/koss-null/funcfrog/internal/algo/parallel/qsort"
+)
+
+func rndInts(n int) []int64 {
+	r := rand.New(rand.NewSource(42))
+	res := make([]int64, n)
+	for i := range res {
+		res[i] = r.Int63() - math.MaxInt64/2
+	}
+	return res
+}
+
+func rndStrings(n int) []string {
+	r := rand.New(rand.NewSource(42))
+	res := make([]string, n)
+	for i := range res {
+		// common prefixes and different lengths
+		res[i] = "key-" + strconv.Itoa(r.Intn(n)) + string(make([]byte, r.Intn(3)))
+	}
+	return res
+}
+
+func int64Key(x *int64) uint64 { return IntKey(*x) }
+
+func Test_Sort(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 50, 1000, 100_000} {
+		for _, threads := range []int{1, 4} {
+			a := rndInts(n)
+			exp := make([]int64, len(a))
+			copy(exp, a)
+			sort.Slice(exp, func(i, j int) bool { return exp[i] < exp[j] })
+			require.Equal(t, exp, Sort(a, int64Key, threads, parallel.Go))
+		}
+	}
+}
+
+func Test_Sort_stable(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ key, pos int }
+	a := make([]pair, 50_000)
+	for i := range a {
+		a[i] = pair{key: i % 7, pos: i}
+	}
+	res := Sort(a, func(x *pair) uint64 { return uint64(x.key) }, 4, parallel.Go)
+	for i := 1; i < len(res); i++ {
+		require.True(t, res[i-1].key < res[i].key || res[i-1].key == res[i].key && res[i-1].pos < res[i].pos)
+	}
+}
+
+func Test_SortStrings(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 50, 1000, 100_000} {
+		for _, threads := range []int{1, 4} {
+			a := rndStrings(n)
+			exp := make([]string, len(a))
+			copy(exp, a)
+			sort.Strings(exp)
+			require.Equal(t, exp, SortStrings(a, func(s *string) string { return *s }, threads, parallel.Go))
+		}
+	}
+
+	same := make([]string, 1000)
+	for i := range same {
+		same[i] = "same"
+	}
+	require.Equal(t, same, SortStrings(append([]string(nil), same...), func(s *string) string { return *s }, 2, parallel.Go))
+}
+
+func Test_Keys(t *testing.T) {
+	t.Parallel()
+
+	ints := []int8{-128, -1, 0, 1, 127}
+	for i := 1; i < len(ints); i++ {
+		require.Less(t, IntKey(ints[i-1]), IntKey(ints[i]))
+	}
+	require.Less(t, IntKey(uint64(1)), IntKey(uint64(math.MaxUint64)))
+
+	floats := []float64{math.Inf(-1), -1.5, -0.5, 0, 0.5, 1.5, math.Inf(1), math.NaN()}
+	for i := 1; i < len(floats); i++ {
+		require.Less(t, FloatKey(floats[i-1]), FloatKey(floats[i]))
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	a := rndInts(1_000_000)
+	data := make([]int64, len(a))
+	for i := 0; i < b.N; i++ {
+		copy(data, a)
+		Sort(data, int64Key, 4, parallel.Go)
+	}
+}
+
+func BenchmarkSortQsort(b *testing.B) {
+	a := rndInts(1_000_000)
+	data := make([]int64, len(a))
+	for i := 0; i < b.N; i++ {
+		copy(data, a)
+		qsort.Sort(data, func(x, y *int64) bool { return *x < *y }, 4)
+	}
+}
+
+func BenchmarkSortStrings(b *testing.B) {
+	a := rndStrings(1_000_000)
+	data := make([]string, len(a))
+	for i := 0; i < b.N; i++ {
+		copy(data, a)
+		SortStrings(data, func(s *string) string { return *s }, 4, parallel.Go)
+	}
+}
+
+func BenchmarkSortStringsQsort(b *testing.B) {
+	a := rndStrings(1_000_000)
+	data := make([]string, len(a))
+	for i := 0; i < b.N; i++ {
+		copy(data, a)
+		qsort.Sort(data, func(x, y *string) bool { return *x < *y }, 4)
+	}
+}
@@ -0,0 +1,40 @@
+// Package parallel holds the way the parallel algorithms start their goroutines.
+package parallel
+
+// Spawn evaluates fn in parallel with the calling goroutine and returns a function waiting for fn to finish.
+// A Spawn may leave fn to the goroutine calling the returned function, so fn must not wait for its spawner.
+type Spawn func(fn func()) (wait func())
+
+// Go is the Spawn starting a new goroutine each time.
+func Go(fn func()) (wait func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	return func() {
+		<-done
+	}
+}
+
+// Parts evaluates fn on threads consecutive parts of [0, n) of the same size but the last one.
+// The first part is evaluated in the calling goroutine, the others are spawned.
+func Parts(spawn Spawn, n, threads int, fn func(lf, rg int)) {
+	if threads <= 1 || n == 0 {
+		fn(0, n)
+		return
+	}
+	step := (n + threads - 1) / threads
+	waits := make([]func(), 0, threads-1)
+	for lf := step; lf < n; lf += step {
+		lf, rg := lf, lf+step
+		if rg > n {
+			rg = n
+		}
+		waits = append(waits, spawn(func() { fn(lf, rg) }))
+	}
+	fn(0, step)
+	for _, wait := range waits {
+		wait()
+	}
+}
@@ -0,0 +1,32 @@
+package parallel
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parts(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 7, 1000} {
+		for _, threads := range []int{1, 3, 8} {
+			var mx sync.Mutex
+			seen := make([]int, n)
+			parts := 0
+			Parts(Go, n, threads, func(lf, rg int) {
+				mx.Lock()
+				defer mx.Unlock()
+				parts++
+				for i := lf; i < rg; i++ {
+					seen[i]++
+				}
+			})
+			for i := range seen {
+				require.Equal(t, 1, seen[i])
+			}
+			require.LessOrEqual(t, parts, threads)
+		}
+	}
+}
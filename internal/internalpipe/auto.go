@@ -0,0 +1,59 @@
+package internalpipe
+
+import (
+	"math"
+	"runtime"
+	"time"
+)
+
+const (
+	// autoSampleCnt is the maximal amount of the first elements evaluated to estimate the cost of an element.
+	autoSampleCnt = 1 << 4
+	// autoSampleTime limits the time spent on the sample.
+	autoSampleTime = 100 * time.Microsecond
+	// autoWorkerWork is the minimal work worth starting one more goroutine.
+	autoWorkerWork = 50 * time.Microsecond
+	// autoChunkWork is the minimal work of a chunk worth taking it from the scheduler.
+	autoChunkWork = 10 * time.Microsecond
+)
+
+// Auto makes the pipe pick the amount of goroutines (up to GOMAXPROCS) and the chunk size
+// on each evaluation by the cost of its first elements.
+// It is applied the same way Parallel() is.
+func (p Pipe[T]) Auto() Pipe[T] {
+	if p.atParallel {
+		return p
+	}
+	p = p.Parallel(uint16(min(runtime.GOMAXPROCS(0), math.MaxUint16)))
+	p.auto = true
+	return p
+}
+
+// sample evaluates the first elements of [from, limit) one by one with body as the worker 0
+// and plans the evaluation of the rest by their cost.
+// It returns the index of the first element left and false if body has stopped the evaluation.
+func (p *Pipe[T]) sample(from, limit int, body func(w, lf, rg int) bool) (int, plan, bool) {
+	start := time.Now()
+	n := 0
+	for n < min(limit-from, autoSampleCnt) && (n == 0 || time.Since(start) < autoSampleTime) {
+		n++
+		if !body(0, from+n-1, from+n) {
+			return from + n, plan{}, false
+		}
+	}
+	if n == 0 {
+		return from, plan{workers: 1}, true
+	}
+	return from + n, autoPlan(time.Since(start)/time.Duration(n), limit-from-n, p.GoroutinesCnt), true
+}
+
+// autoPlan picks the amount of workers up to maxWorkers and the grain to evaluate remaining elements of cost each.
+// A worker is started only if it gets at least autoWorkerWork, so cheap pipes are evaluated in a single goroutine.
+func autoPlan(cost time.Duration, remaining, maxWorkers int) plan {
+	cost = max(cost, 1)
+	perWorker := max(int(autoWorkerWork/cost), 1)
+	return plan{
+		workers: max(min(remaining/perWorker, maxWorkers), 1),
+		grain:   min(max(int(autoChunkWork/cost), 1), maxChunk),
+	}
+}
@@ -0,0 +1,64 @@
+package internalpipe
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_autoPlan(t *testing.T) {
+	t.Parallel()
+
+	// cheap elements are not worth more goroutines
+	require.Equal(t, plan{workers: 1, grain: 1000}, autoPlan(10*time.Nanosecond, 1000, 8))
+	// but there are enough of them
+	require.Equal(t, plan{workers: 8, grain: 1000}, autoPlan(10*time.Nanosecond, 1_000_000, 8))
+	// expensive elements are evaluated one by one in all the goroutines available
+	require.Equal(t, plan{workers: 8, grain: 1}, autoPlan(time.Millisecond, 100, 8))
+	require.Equal(t, plan{workers: 3, grain: 1}, autoPlan(time.Millisecond, 3, 8))
+	require.Equal(t, plan{workers: 1, grain: 1}, autoPlan(time.Millisecond, 0, 8))
+	require.Equal(t, plan{workers: 1, grain: 10_000}, autoPlan(0, 10, 8))
+}
+
+func Test_Auto(t *testing.T) {
+	t.Parallel()
+
+	p := Slice([]int{1, 2, 3}).Auto()
+	require.True(t, p.auto)
+	require.Equal(t, runtime.GOMAXPROCS(0), p.GoroutinesCnt)
+	require.Equal(t, p.GoroutinesCnt, p.Parallel(5).GoroutinesCnt)
+	require.Empty(t, p.Parallel(5).stages)
+	require.True(t, p.Map(func(x int) int { return x }).auto)
+
+	require.False(t, Slice([]int{1}).Parallel(3).Auto().auto)
+
+	slow := func(x int) int {
+		for start := time.Now(); time.Since(start) < 10*time.Microsecond; {
+		}
+		return x * 2
+	}
+	a := make([]int, 1000)
+	for i := range a {
+		a[i] = i
+	}
+	for _, p := range []Pipe[int]{
+		Slice(a).Map(slow).Auto(),
+		Slice(a).Map(slow).Parallel(4).Auto(),
+		Slice(a).Auto().Map(slow),
+		Slice(a).Map(slow).Parallel(4).Filter(func(*int) bool { return true }).Auto(),
+	} {
+		res := p.Do()
+		require.Len(t, res, len(a))
+		for i := range res {
+			require.Equal(t, 2*i, res[i])
+		}
+		require.Equal(t, 1000*999, p.Sum(func(x, y *int) int { return *x + *y }))
+		require.Equal(t, 0, *p.First())
+		require.Equal(t, 2*999, *p.Max(func(x, y *int) bool { return *x < *y }))
+	}
+
+	res := Func(func(i int) (int, bool) { return i, i%2 == 0 }).Auto().Filter(func(x *int) bool { return *x > 10 }).First()
+	require.Equal(t, 12, *res)
+}
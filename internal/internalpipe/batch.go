@@ -0,0 +1,145 @@
+package internalpipe
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// batchBlock is the amount of elements passed through all the stages of a pipe at once.
+const batchBlock = 1 << 8
+
+var skippedPool = sync.Pool{
+	New: func() any {
+		return new([batchBlock]bool)
+	},
+}
+
+// doBatch evaluates the pipe block by block with p.BatchFn.
+func (p *Pipe[T]) doBatch(needResult bool) ([]T, int) {
+	var vals []T
+	var skipped []bool
+	if needResult {
+		vals, skipped = make([]T, p.Len), make([]bool, p.Len)
+	}
+
+	var skipCnt int
+	if p.GoroutinesCnt == 1 && !p.auto && len(p.stages) == 0 {
+		skipCnt = batches(p.BatchFn, 0, p.Len, vals, skipped)
+	} else {
+		skipCnt = p.scheduleBatches(vals, skipped)
+	}
+
+	cnt := p.Len - skipCnt
+	if !needResult || cnt == p.Len {
+		return vals, cnt
+	}
+
+	res := vals[:0]
+	if cnt < p.Len/2 {
+		// the rest of the space is not kept
+		res = make([]T, 0, cnt)
+	}
+	for i := range vals {
+		if !skipped[i] {
+			res = append(res, vals[i])
+		}
+	}
+	return res, cnt
+}
+
+func (p *Pipe[T]) scheduleBatches(vals []T, skipped []bool) int {
+	batchFn := p.BatchFn
+	var skipCnt atomic.Int64
+	p.schedule(func(_, lf, rg int) bool {
+		skipCnt.Add(int64(batches(batchFn, lf, rg, vals, skipped)))
+		return true
+	})
+	return int(skipCnt.Load())
+}
+
+// batches evaluates [lf, rg) block by block with batchFn and returns the amount of skipped elements.
+// The values and the skipped flags are written into vals and skipped if they are not nil.
+func batches[T any](batchFn BatchFn[T], lf, rg int, vals []T, skipped []bool) int {
+	var out []T
+	if vals == nil {
+		out = make([]T, min(batchBlock, rg-lf))
+	}
+	var scratch *[batchBlock]bool
+	if skipped == nil {
+		scratch = skippedPool.Get().(*[batchBlock]bool)
+		defer skippedPool.Put(scratch)
+	}
+
+	skipCnt := 0
+	for b := lf; b < rg; b += batchBlock {
+		e := min(b+batchBlock, rg)
+		o, s := out, []bool(nil)
+		if vals != nil {
+			o = vals[b:e]
+		}
+		if skipped != nil {
+			s = skipped[b:e]
+		} else {
+			s = scratch[:e-b]
+		}
+
+		batchFn(b, e, o, s)
+		for _, sk := range s {
+			if sk {
+				skipCnt++
+			}
+		}
+	}
+	return skipCnt
+}
+
+// sumBatch sums the pipe up block by block with p.BatchFn.
+func (p *Pipe[T]) sumBatch(plus AccumFn[T]) T {
+	if p.GoroutinesCnt == 1 && !p.auto && len(p.stages) == 0 {
+		res, _ := sumBatches(p.BatchFn, 0, p.Len, plus)
+		return res
+	}
+
+	batchFn := p.BatchFn
+	parts := make([]T, p.GoroutinesCnt)
+	p.schedule(func(w, lf, rg int) bool {
+		if inRes, found := sumBatches(batchFn, lf, rg, plus); found {
+			parts[w] = plus(&parts[w], &inRes)
+		}
+		return true
+	})
+
+	var res T
+	for i := range parts {
+		res = plus(&res, &parts[i])
+	}
+	return res
+}
+
+// sumBatches sums up [lf, rg) starting with the first not skipped element, it returns false if there is none.
+func sumBatches[T any](batchFn BatchFn[T], lf, rg int, plus AccumFn[T]) (T, bool) {
+	var res T
+	if lf >= rg {
+		return res, false
+	}
+
+	out := make([]T, min(batchBlock, rg-lf))
+	s := skippedPool.Get().(*[batchBlock]bool)
+	defer skippedPool.Put(s)
+
+	found := false
+	for b := lf; b < rg; b += batchBlock {
+		e := min(b+batchBlock, rg)
+		batchFn(b, e, out, s[:e-b])
+		for j := range out[:e-b] {
+			switch {
+			case s[j]:
+			case found:
+				res = plus(&res, &out[j])
+			default:
+				res, found = out[j], true
+			}
+		}
+	}
+	return res, found
+}
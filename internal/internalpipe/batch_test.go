@@ -0,0 +1,84 @@
+package internalpipe
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_doBatch(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = i
+	}
+	chain := func(p Pipe[int]) Pipe[int] {
+		return p.Map(func(x int) int { return x + 1 }).
+			Filter(func(x *int) bool { return *x%3 != 0 }).
+			MapFilter(func(x int) (int, bool) { return x * 2, x%5 != 0 }).
+			Map(func(x int) int { return x - 1 })
+	}
+	// the same pipe evaluated element by element
+	byElement := func(p Pipe[int]) Pipe[int] {
+		p.ValFn, p.BatchFn = nil, nil
+		return p
+	}
+
+	for _, cnt := range []uint16{1, 7} {
+		cnt := cnt
+		t.Run("equal to the per element evaluation "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			p := chain(Slice(a)).Parallel(cnt)
+			require.NotNil(t, p.BatchFn)
+			exp := chain(byElement(Slice(a))).Parallel(cnt)
+			require.Nil(t, exp.BatchFn)
+
+			require.Equal(t, exp.Do(), p.Do())
+			require.Equal(t, exp.Count(), p.Count())
+			sum := func(x, y *int) int { return *x + *y }
+			require.Equal(t, exp.Sum(sum), p.Sum(sum))
+			require.Equal(t, *exp.First(), *p.First())
+		})
+	}
+
+	t.Run("range and repeat", func(t *testing.T) {
+		t.Parallel()
+
+		r := Range(0, 1000, 3).Filter(func(x *int) bool { return *x%2 == 0 })
+		require.Equal(t, byElement(r).Do(), r.Do())
+		rp := Repeat(2, 1000).MapFilter(func(x int) (int, bool) { return x, true })
+		require.Equal(t, 2000, rp.Sum(func(x, y *int) int { return *x + *y }))
+	})
+
+	t.Run("type change", func(t *testing.T) {
+		t.Parallel()
+
+		p := MapTo(Slice(a).Filter(func(x *int) bool { return *x%2 == 0 }), strconv.Itoa)
+		require.NotNil(t, p.BatchFn)
+		res := p.Do()
+		require.Len(t, res, len(a)/2)
+		for i, s := range res {
+			require.Equal(t, strconv.Itoa(2*i), s)
+		}
+	})
+
+	t.Run("all skipped", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a).Filter(func(*int) bool { return false })
+		require.Empty(t, p.Do())
+		require.Zero(t, p.Count())
+		require.Zero(t, p.Sum(func(x, y *int) int { return *x + *y }))
+	})
+
+	t.Run("no batches", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, Func(func(i int) (int, bool) { return i, true }).Gen(10).Map(func(x int) int { return x }).BatchFn)
+		require.Nil(t, Slice(a).Erase().BatchFn)
+		require.Nil(t, Slice(a).Parallel(2).Map(func(x int) int { return x }).Parallel(3).BatchFn)
+	})
+}
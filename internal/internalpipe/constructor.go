@@ -19,6 +19,13 @@ func Slice[T any](dt []T) Pipe[T] {
 			}
 			return &dt[i], false
 		},
+		ValFn: func(i int) T {
+			return dt[i]
+		},
+		BatchFn: func(lf, rg int, out []T, skipped []bool) {
+			copy(out, dt[lf:rg])
+			unskip(skipped[:rg-lf])
+		},
 		Len:           len(dt),
 		ValLim:        notSet,
 		GoroutinesCnt: defaultParallelWrks,
@@ -81,15 +88,28 @@ func Range[T constraints.Integer | constraints.Float](start, finish, step T) Pip
 			return x <= finish
 		}
 	}
-	return Pipe[T]{
+	p := Pipe[T]{
 		Fn: func(i int) (*T, bool) {
 			val := start + T(i)*step
 			return &val, pred(val)
 		},
+		BatchFn: func(lf, rg int, out []T, skipped []bool) {
+			for j := range out[:rg-lf] {
+				out[j] = start + T(lf+j)*step
+				skipped[j] = pred(out[j])
+			}
+		},
 		Len:           ceil(float64(finish-start) / float64(step)),
 		ValLim:        notSet,
 		GoroutinesCnt: defaultParallelWrks,
 	}
+	// the floating point step may make the last element to be skipped
+	if !pred(start + T(p.Len-1)*step) {
+		p.ValFn = func(i int) T {
+			return start + T(i)*step
+		}
+	}
+	return p
 }
 
 func Repeat[T any](x T, n int) Pipe[T] {
@@ -107,6 +127,15 @@ func Repeat[T any](x T, n int) Pipe[T] {
 			cp := x
 			return &cp, i >= n
 		},
+		ValFn: func(int) T {
+			return x
+		},
+		BatchFn: func(lf, rg int, out []T, skipped []bool) {
+			for j := range out[:rg-lf] {
+				out[j] = x
+			}
+			unskip(skipped[:rg-lf])
+		},
 		Len:           n,
 		ValLim:        notSet,
 		GoroutinesCnt: defaultParallelWrks,
@@ -116,3 +145,9 @@ func Repeat[T any](x T, n int) Pipe[T] {
 func ceil[T constraints.Integer | constraints.Float](a T) int {
 	return int(math.Ceil(float64(a)))
 }
+
+func unskip(skipped []bool) {
+	for j := range skipped {
+		skipped[j] = false
+	}
+}
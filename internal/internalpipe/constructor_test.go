@@ -96,6 +96,17 @@ func Test_Range(t *testing.T) {
 			require.Equal(t, i, res[i])
 		}
 	})
+	t.Run("float", func(t *testing.T) {
+		t.Parallel()
+		p := Range(0, 1, 0.1)
+		require.NotNil(t, p.ValFn)
+		res := p.Do()
+		require.Equal(t, 10, len(res))
+		require.InDelta(t, 0.9, res[9], 1e-9)
+		for i := range res {
+			require.Equal(t, res[i], p.ValFn(i))
+		}
+	})
 	t.Run("single_step_owerflow", func(t *testing.T) {
 		t.Parallel()
 		p := Range(1, 10, 50)
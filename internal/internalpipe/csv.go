@@ -0,0 +1,279 @@
+package internalpipe
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// CSVOptions sets up the reading and the writing of CSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if not set.
+	Comma rune
+	// Comment starts a comment line being skipped on reading if set.
+	Comment rune
+	// LazyQuotes allows quotes in unquoted fields and not doubled quotes in quoted fields on reading.
+	LazyQuotes bool
+	// TrimLeadingSpace trims the leading white space of the fields on reading.
+	TrimLeadingSpace bool
+	// UseCRLF ends the lines with \r\n on writing.
+	UseCRLF bool
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// csvField is a field of a struct mapped to a CSV column.
+type csvField struct {
+	name   string
+	index  []int
+	parse  func(reflect.Value, string) error
+	format func(reflect.Value) (string, error)
+}
+
+// csvRecord is a record of CSV along with the line it starts at.
+type csvRecord struct {
+	fields []string
+	line   int
+}
+
+// CSV creates a pipe of the structs T made of the records of r, the length is unknown.
+// The first record is the header: its columns are mapped to the fields of T by the csv tag or the field name,
+// the other columns are ignored. The records are split in order and parsed into T by the goroutines evaluating
+// the pipe, the empty values leave the fields zero.
+// A malformed record is skipped and its LineError is yeeted to the Yeti of the pipe.
+func CSV[T any](r io.Reader, opts CSVOptions) Pipe[T] {
+	y := newRelay()
+	fields, err := csvFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		y.Yeet(err)
+		return sequence(y, func() (T, bool) {
+			var zero T
+			return zero, false
+		}, nil)
+	}
+
+	cr := csvReader(r, opts)
+	var cols []*csvField
+	records := sequence(y, func() (csvRecord, bool) {
+		for {
+			rec, err := cr.Read()
+			var pe *csv.ParseError
+			switch {
+			case errors.Is(err, io.EOF):
+				return csvRecord{}, false
+			case errors.As(err, &pe):
+				y.Yeet(&LineError{Line: pe.StartLine, Err: err})
+				continue
+			case err != nil:
+				y.Yeet(err)
+				return csvRecord{}, false
+			}
+
+			line, _ := cr.FieldPos(0)
+			if cols == nil {
+				cols = csvColumns(fields, rec)
+				continue
+			}
+			return csvRecord{fields: rec, line: line}, true
+		}
+	}, nil)
+
+	return Derive(records, func(i int) (*T, bool) {
+		rec, skipped := records.Fn(i)
+		if skipped {
+			return nil, true
+		}
+		var obj T
+		v := reflect.ValueOf(&obj).Elem()
+		for j, val := range rec.fields {
+			f := cols[j]
+			if f == nil || val == "" {
+				continue
+			}
+			if err := f.parse(v.FieldByIndex(f.index), val); err != nil {
+				y.Yeet(&LineError{Line: rec.line, Err: fmt.Errorf("column %q: %w", f.name, err)})
+				return nil, true
+			}
+		}
+		return &obj, false
+	})
+}
+
+func csvReader(r io.Reader, opts CSVOptions) *csv.Reader {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.Comment = opts.Comment
+	cr.LazyQuotes = opts.LazyQuotes
+	cr.TrimLeadingSpace = opts.TrimLeadingSpace
+	return cr
+}
+
+// csvColumns returns the fields mapped to the columns of the header, nil for the columns not mapped.
+func csvColumns(fields []csvField, header []string) []*csvField {
+	byName := make(map[string]*csvField, len(fields))
+	for i := range fields {
+		byName[fields[i].name] = &fields[i]
+	}
+	cols := make([]*csvField, len(header))
+	for j, name := range header {
+		cols[j] = byName[name]
+	}
+	return cols
+}
+
+// WriteCSV writes the elements of the pipe to w as the records of CSV in the order of the pipe
+// after the header made of the fields of T the way CSV maps them.
+// The records are formatted the way WriteTo formats the elements.
+// It stops at the first element failed to be formatted or written and returns the error,
+// the error of the context of the pipe once it's done.
+func WriteCSV[T any](w io.Writer, p Pipe[T], opts CSVOptions) error {
+	fields, err := csvFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+
+	header := make([]string, len(fields))
+	for i := range fields {
+		header[i] = fields[i].name
+	}
+	var hb bytes.Buffer
+	if err := writeRecord(&hb, header, opts); err != nil {
+		return err
+	}
+	if _, err := w.Write(hb.Bytes()); err != nil {
+		return err
+	}
+
+	_, err = writeTo(w, p, func(x *T, b []byte) ([]byte, error) {
+		v := reflect.ValueOf(x).Elem()
+		rec := make([]string, len(fields))
+		for i := range fields {
+			s, err := fields[i].format(v.FieldByIndex(fields[i].index))
+			if err != nil {
+				return b, fmt.Errorf("field %q: %w", fields[i].name, err)
+			}
+			rec[i] = s
+		}
+		buf := bytes.NewBuffer(b)
+		err := writeRecord(buf, rec, opts)
+		return buf.Bytes(), err
+	})
+	return err
+}
+
+func writeRecord(w io.Writer, rec []string, opts CSVOptions) error {
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	cw.UseCRLF = opts.UseCRLF
+	if err := cw.Write(rec); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvFields returns the exported fields of the struct t including the ones of its embedded structs.
+// A field is named by its csv tag or its name, the fields tagged with "-" are skipped.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: %s is not a struct", t)
+	}
+
+	var fields []csvField
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() || sf.Anonymous || !embeddedByValue(t, sf.Index) {
+			continue
+		}
+		name := sf.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		parse, format, err := csvCodec(sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("csv: field %s: %w", sf.Name, err)
+		}
+		fields = append(fields, csvField{name: name, index: sf.Index, parse: parse, format: format})
+	}
+	return fields, nil
+}
+
+// embeddedByValue reports if the field at index is reached with no embedded pointers on the way.
+func embeddedByValue(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+	}
+	return true
+}
+
+// csvCodec returns the functions parsing and formatting the values of type t.
+func csvCodec(t reflect.Type) (func(reflect.Value, string) error, func(reflect.Value) (string, error), error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return func(v reflect.Value, s string) error {
+				return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+			}, func(v reflect.Value) (string, error) {
+				b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+				return string(b), err
+			}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value, s string) error {
+				v.SetString(s)
+				return nil
+			}, func(v reflect.Value) (string, error) {
+				return v.String(), nil
+			}, nil
+	case reflect.Bool:
+		return func(v reflect.Value, s string) error {
+				b, err := strconv.ParseBool(s)
+				v.SetBool(b)
+				return err
+			}, func(v reflect.Value) (string, error) {
+				return strconv.FormatBool(v.Bool()), nil
+			}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, s string) error {
+				n, err := strconv.ParseInt(s, 10, t.Bits())
+				v.SetInt(n)
+				return err
+			}, func(v reflect.Value) (string, error) {
+				return strconv.FormatInt(v.Int(), 10), nil
+			}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value, s string) error {
+				n, err := strconv.ParseUint(s, 10, t.Bits())
+				v.SetUint(n)
+				return err
+			}, func(v reflect.Value) (string, error) {
+				return strconv.FormatUint(v.Uint(), 10), nil
+			}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, s string) error {
+				f, err := strconv.ParseFloat(s, t.Bits())
+				v.SetFloat(f)
+				return err
+			}, func(v reflect.Value) (string, error) {
+				return strconv.FormatFloat(v.Float(), 'g', -1, t.Bits()), nil
+			}, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported type %s", t)
+}
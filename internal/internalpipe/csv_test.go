@@ -0,0 +1,121 @@
+package internalpipe
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type audit struct {
+	At time.Time `csv:"at"`
+}
+
+type sale struct {
+	audit
+	ID     int     `csv:"id"`
+	Item   string  `csv:"item"`
+	Price  float64 `csv:"price"`
+	Paid   bool
+	secret string
+	Note   string `csv:"-"`
+}
+
+func Test_csvFields(t *testing.T) {
+	t.Parallel()
+
+	fields, err := csvFields(reflect.TypeOf(sale{}))
+	require.NoError(t, err)
+	names := make([]string, len(fields))
+	for i := range fields {
+		names[i] = fields[i].name
+	}
+	require.Equal(t, []string{"at", "id", "item", "price", "Paid"}, names)
+
+	_, err = csvFields(reflect.TypeOf(0))
+	require.Error(t, err)
+	_, err = csvFields(reflect.TypeOf(struct{ C chan int }{}))
+	require.Error(t, err)
+}
+
+func Test_CSV(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var sb strings.Builder
+	sb.WriteString("item,id,unknown,price,Paid,at\n")
+	exp := make([]sale, 10_000)
+	for i := range exp {
+		exp[i] = sale{audit: audit{At: at}, ID: i, Item: "item " + strconv.Itoa(i), Price: float64(i) / 4, Paid: i%2 == 0}
+		sb.WriteString("\"item " + strconv.Itoa(i) + "\"," + strconv.Itoa(i) + ",x," +
+			strconv.FormatFloat(exp[i].Price, 'g', -1, 64) + "," + strconv.FormatBool(exp[i].Paid) + "," +
+			at.Format(time.RFC3339) + "\n")
+	}
+	require.Equal(t, exp, CSV[sale](strings.NewReader(sb.String()), CSVOptions{}).Parallel(8).Do())
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+
+		in := "# comment\nid; item\n1; \"a;b\"\n2;\n"
+		res := CSV[sale](strings.NewReader(in), CSVOptions{Comma: ';', Comment: '#', TrimLeadingSpace: true}).Do()
+		require.Equal(t, []sale{{ID: 1, Item: "a;b"}, {ID: 2}}, res)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		in := "id,price\n1,1.5\nx,2\n3\n4,\"5\n5,5\n"
+		var handled []error
+		res := CSV[sale](strings.NewReader(in), CSVOptions{}).
+			Yeti(NewYeti()).
+			Snag(func(err error) { handled = append(handled, err) }).
+			Parallel(4).
+			Do()
+		require.Equal(t, []sale{{ID: 1, Price: 1.5}}, res)
+
+		lines := make([]int, 0, len(handled))
+		for _, err := range handled {
+			var le *LineError
+			require.True(t, errors.As(err, &le))
+			lines = append(lines, le.Line)
+		}
+		require.ElementsMatch(t, []int{3, 4, 5}, lines)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		t.Parallel()
+
+		var handled error
+		res := CSV[int](strings.NewReader("a\n1\n"), CSVOptions{}).
+			Snag(func(err error) { handled = err }).
+			Do()
+		require.Empty(t, res)
+		require.Error(t, handled)
+	})
+}
+
+func Test_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sales := make([]sale, 20_000)
+	for i := range sales {
+		sales[i] = sale{audit: audit{At: at}, ID: i, Item: "a,\"b\" " + strconv.Itoa(i), Price: float64(i) / 8, Paid: i%3 == 0}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, Slice(sales).Parallel(8), CSVOptions{}))
+	require.True(t, strings.HasPrefix(buf.String(), "at,id,item,price,Paid\n2024-01-02T03:04:05Z,0,\"a,\"\"b\"\" 0\",0,true\n"))
+	require.Equal(t, sales, CSV[sale](&buf, CSVOptions{}).Parallel(8).Do())
+
+	buf.Reset()
+	require.NoError(t, WriteCSV(&buf, Slice(sales[:1]), CSVOptions{Comma: ';', UseCRLF: true}))
+	require.Equal(t, "at;id;item;price;Paid\r\n2024-01-02T03:04:05Z;0;\"a,\"\"b\"\" 0\";0;true\r\n", buf.String())
+
+	require.Error(t, WriteCSV(&buf, Slice([]int{1}), CSVOptions{}))
+}
@@ -1,10 +1,6 @@
 package internalpipe
 
-import (
-	"math"
-	"sync"
-	"sync/atomic"
-)
+import "sync/atomic"
 
 type ev[T any] struct {
 	skipped bool
@@ -13,82 +9,52 @@ type ev[T any] struct {
 
 // Do evaluates all the pipeline and returns the result slice.
 func (p Pipe[T]) Do() []T {
-	if p.limitSet() {
-		res := p.doToLimit()
-		return res
-	}
 	res, _ := p.do(true)
 	return res
 }
 
-// doToLimit executor for Take
-func (p *Pipe[T]) doToLimit() []T {
-	if p.ValLim == 0 {
-		return []T{}
-	}
-
-	if p.y != nil {
-		defer p.y.Handle()
-	}
-
-	res := make([]T, 0, p.ValLim)
-	for i := 0; len(res) < p.ValLim; i++ {
-		obj, skipped := p.Fn(i)
-		if !skipped {
-			res = append(res, *obj)
-		}
-
-		if i == math.MaxInt {
-			panic(panicLimitExceededMsg)
-		}
-	}
-	return res
-}
-
 // do runs the result evaluation.
 func (p *Pipe[T]) do(needResult bool) ([]T, int) {
 	if p.y != nil {
 		defer p.y.Handle()
 	}
+	defer p.open()()
+
+	switch {
+	case !p.lenSet():
+		return p.stream(needResult, nil)
+	case p.ValFn != nil && p.lenSet():
+		return p.doValues(needResult), p.Len
+	case p.BatchFn != nil && p.lenSet():
+		return p.doBatch(needResult)
+	}
 
 	var (
 		eval    []ev[T]
+		fn      = p.Fn
 		limit   = p.limit()
-		step    = max(divUp(limit, p.GoroutinesCnt), 1)
-		wg      sync.WaitGroup
 		skipCnt atomic.Int64
 	)
 	if needResult && limit > 0 {
 		eval = make([]ev[T], limit)
 	}
-	tickets := genTickets(p.GoroutinesCnt)
-	for i := 0; i > -1 && i < limit; i += step {
-		<-tickets
-		wg.Add(1)
-		go func(lf, rg int) {
-			if rg < 0 {
-				rg = limit
+	p.schedule(func(_, lf, rg int) bool {
+		var sCnt int64
+		for j := lf; j < rg; j++ {
+			obj, skipped := fn(j)
+			if skipped {
+				sCnt++
 			}
-			rg = min(rg, limit)
-			var sCnt int64
-			for j := lf; j < rg; j++ {
-				obj, skipped := p.Fn(j)
-				if skipped {
-					sCnt++
-				}
-				if needResult {
-					eval[j] = ev[T]{
-						obj:     obj,
-						skipped: skipped,
-					}
+			if needResult {
+				eval[j] = ev[T]{
+					obj:     obj,
+					skipped: skipped,
 				}
 			}
-			skipCnt.Add(sCnt)
-			tickets <- struct{}{}
-			wg.Done()
-		}(i, i+step)
-	}
-	wg.Wait()
+		}
+		skipCnt.Add(sCnt)
+		return true
+	})
 
 	res := make([]T, 0, limit-int(skipCnt.Load()))
 	for i := range eval {
@@ -98,3 +64,38 @@ func (p *Pipe[T]) do(needResult bool) ([]T, int) {
 	}
 	return res, limit - int(skipCnt.Load())
 }
+
+// doValues evaluates the pipe not skipping any element writing the values straight into the result.
+func (p *Pipe[T]) doValues(needResult bool) []T {
+	var res []T
+	if needResult {
+		res = make([]T, p.Len)
+	}
+	if p.GoroutinesCnt == 1 && !p.auto && len(p.stages) == 0 {
+		values(p.ValFn, res, 0, p.Len)
+		return res
+	}
+	p.scheduleValues(res)
+	return res
+}
+
+func (p *Pipe[T]) scheduleValues(res []T) {
+	valFn := p.ValFn
+	p.schedule(func(_, lf, rg int) bool {
+		values(valFn, res, lf, rg)
+		return true
+	})
+}
+
+// values evaluates valFn on [lf, rg) writing the values into res unless it's nil.
+func values[T any](valFn func(int) T, res []T, lf, rg int) {
+	if res == nil {
+		for j := lf; j < rg; j++ {
+			valFn(j)
+		}
+		return
+	}
+	for j := lf; j < rg; j++ {
+		res[j] = valFn(j)
+	}
+}
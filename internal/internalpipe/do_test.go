@@ -95,4 +95,34 @@ func Test_Do(t *testing.T) {
 			require.Equal(t, exp[i], r)
 		}
 	})
+
+	t.Run("not skipping pipe", func(t *testing.T) {
+		t.Parallel()
+
+		a := make([]int, 100_000)
+		for i := range a {
+			a[i] = i
+		}
+		for _, cnt := range []uint16{1, 7} {
+			p := Slice(a).Map(func(x int) int { return x + 1 }).Parallel(cnt)
+			require.NotNil(t, p.ValFn)
+			res := p.Do()
+			require.Len(t, res, len(a))
+			for i, r := range res {
+				require.Equal(t, i+1, r)
+			}
+			require.Equal(t, len(a), p.Count())
+		}
+
+		require.Nil(t, Slice(a).Filter(func(*int) bool { return true }).Map(func(x int) int { return x }).ValFn)
+	})
+}
+
+func Test_doValues_allocs(t *testing.T) {
+	a := make([]int, 100_000)
+	p := Slice(a).Map(func(x int) int { return x + 1 })
+
+	// the result slice is the only allocation
+	require.Equal(t, float64(1), testing.AllocsPerRun(10, func() { p.Do() }))
+	require.Zero(t, testing.AllocsPerRun(10, func() { p.Count() }))
 }
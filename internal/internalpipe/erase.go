@@ -13,6 +13,12 @@ func (p Pipe[T]) Erase() Pipe[any] {
 		ValLim:        p.ValLim,
 		GoroutinesCnt: p.GoroutinesCnt,
 
-		y: p.y,
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+		stages:      p.stages,
 	}
 }
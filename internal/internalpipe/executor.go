@@ -0,0 +1,242 @@
+package internalpipe
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultExecutorSize is the amount of goroutines of the process-wide default executor.
+const DefaultExecutorSize = 256
+
+var defaultExecutor atomic.Pointer[Executor]
+
+func init() {
+	defaultExecutor.Store(NewExecutor(DefaultExecutorSize))
+}
+
+// DefaultExecutor returns the executor used by the pipes with no executor set.
+func DefaultExecutor() *Executor {
+	return defaultExecutor.Load()
+}
+
+// SetDefaultExecutor replaces the executor used by the pipes with no executor set.
+// The previous default executor is not closed. nil is ignored.
+func SetDefaultExecutor(ex *Executor) {
+	if ex != nil {
+		defaultExecutor.Store(ex)
+	}
+}
+
+// ExecutorMetrics is a snapshot of the Executor state.
+type ExecutorMetrics struct {
+	// MaxWorkers is the maximal amount of goroutines in the pool.
+	MaxWorkers int
+	// Workers is the amount of goroutines started by the pool.
+	Workers int
+	// Busy is the amount of goroutines evaluating a pipe right now.
+	Busy int
+	// Jobs is the amount of pipe evaluations waiting for the pool goroutines.
+	Jobs int
+	// Queued is the amount of workers waiting for the pool goroutines.
+	Queued int
+	// Submitted is the total amount of workers submitted to the pool.
+	Submitted int64
+	// Completed is the total amount of workers evaluated by the pool.
+	Completed int64
+	// Dropped is the total amount of workers dropped since their pipe was evaluated before they started.
+	Dropped int64
+}
+
+// job is a terminal operation evaluation with its workers queued to the executor.
+type job struct {
+	task    func(w int)
+	nextW   int
+	pending int
+	wg      sync.WaitGroup
+}
+
+// Executor is a bounded pool of goroutines shared by the pipes.
+// Each terminal operation runs its first worker in the calling goroutine and queues the rest as a job.
+// The free pool goroutines take the queued workers from the jobs in turn, so a pipe with many workers
+// does not hold back the others. The workers still queued when their pipe is evaluated are dropped,
+// so a terminal operation never waits for a free goroutine and nested pipes can't deadlock the pool.
+type Executor struct {
+	mx   sync.Mutex
+	cond *sync.Cond
+
+	jobs   []*job
+	next   int
+	size   int
+	closed bool
+
+	workers, idle, busy           int
+	submitted, completed, dropped int64
+}
+
+// NewExecutor creates an Executor running at most maxWorkers goroutines.
+// The goroutines are started on demand and live until Close is called.
+// The goroutines calling the terminal operations are not counted, so an Executor of 0 workers
+// evaluates each pipe in its calling goroutine.
+func NewExecutor(maxWorkers int) *Executor {
+	e := &Executor{size: max(maxWorkers, 0)}
+	e.cond = sync.NewCond(&e.mx)
+	return e
+}
+
+// Close stops the pool goroutines after they finish the queued workers.
+// The pipes evaluated on a closed Executor are evaluated in their calling goroutines.
+func (e *Executor) Close() {
+	e.mx.Lock()
+	e.closed = true
+	e.mx.Unlock()
+	e.cond.Broadcast()
+}
+
+// Metrics returns the current state of the Executor.
+func (e *Executor) Metrics() ExecutorMetrics {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	queued := 0
+	for _, j := range e.jobs {
+		queued += j.pending
+	}
+	return ExecutorMetrics{
+		MaxWorkers: e.size,
+		Workers:    e.workers,
+		Busy:       e.busy,
+		Jobs:       len(e.jobs),
+		Queued:     queued,
+		Submitted:  e.submitted,
+		Completed:  e.completed,
+		Dropped:    e.dropped,
+	}
+}
+
+// run evaluates task(0) in the calling goroutine and task(1), ..., task(n-1) in the pool.
+// It returns when task(0) and all the started tasks are done; the tasks not started by then are dropped,
+// so task(0) must be able to do all the work alone.
+func (e *Executor) run(n int, task func(w int)) {
+	if n < 2 {
+		task(0)
+		return
+	}
+	wait := e.start(n-1, func(w int) {
+		task(w + 1)
+	})
+	task(0)
+	wait()
+}
+
+// start queues task(0), ..., task(n-1) to the pool and returns right away.
+// The returned function drops the tasks not started yet and waits for the started ones to finish.
+func (e *Executor) start(n int, task func(w int)) (wait func()) {
+	e.mx.Lock()
+	if n < 1 || e.closed || e.size == 0 {
+		e.mx.Unlock()
+		return noop
+	}
+
+	j := &job{task: task, pending: n}
+	e.jobs = append(e.jobs, j)
+	e.submitted += int64(j.pending)
+	e.wake(j.pending)
+	e.mx.Unlock()
+
+	return func() {
+		e.mx.Lock()
+		if j.pending != 0 {
+			e.dropped += int64(j.pending)
+			j.pending = 0
+			e.remove(j)
+		}
+		e.mx.Unlock()
+		j.wg.Wait()
+	}
+}
+
+// spawn queues fn to the pool and returns a function waiting for it.
+// fn is evaluated in the goroutine calling the returned function if no pool goroutine has started it by then.
+func (e *Executor) spawn(fn func()) (wait func()) {
+	started := false
+	waitStarted := e.start(1, func(int) {
+		started = true
+		fn()
+	})
+	return func() {
+		waitStarted()
+		if !started {
+			fn()
+		}
+	}
+}
+
+// wake makes n more goroutines take the queued workers: idle ones first, then the new ones.
+func (e *Executor) wake(n int) {
+	woken := min(n, e.idle)
+	e.idle -= woken
+	for i := 0; i < woken; i++ {
+		e.cond.Signal()
+	}
+	for i := 0; i < min(n-woken, e.size-e.workers); i++ {
+		e.workers++
+		go e.loop()
+	}
+}
+
+// take returns the next queued worker taking the jobs in turn.
+func (e *Executor) take() (*job, int) {
+	e.next %= len(e.jobs)
+	j := e.jobs[e.next]
+	w := j.nextW
+	j.nextW++
+	j.pending--
+	j.wg.Add(1)
+	if j.pending == 0 {
+		e.remove(j)
+	} else {
+		e.next++
+	}
+	return j, w
+}
+
+func (e *Executor) remove(j *job) {
+	for i := range e.jobs {
+		if e.jobs[i] == j {
+			e.jobs = append(e.jobs[:i], e.jobs[i+1:]...)
+			if i < e.next {
+				e.next--
+			}
+			return
+		}
+	}
+}
+
+func (e *Executor) loop() {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	for {
+		for len(e.jobs) == 0 && !e.closed {
+			// the goroutine is counted as idle until wake signals it
+			e.idle++
+			e.cond.Wait()
+		}
+		if len(e.jobs) == 0 {
+			e.workers--
+			e.idle = 0
+			return
+		}
+
+		j, w := e.take()
+		e.busy++
+		e.mx.Unlock()
+
+		j.task(w)
+		j.wg.Done()
+
+		e.mx.Lock()
+		e.busy--
+		e.completed++
+	}
+}
@@ -0,0 +1,161 @@
+package internalpipe
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bounded", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(3)
+		defer ex.Close()
+
+		var running, maxRunning atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res := Func(func(i int) (int, bool) {
+					cur := running.Add(1)
+					for prev := maxRunning.Load(); cur > prev && !maxRunning.CompareAndSwap(prev, cur); {
+						prev = maxRunning.Load()
+					}
+					running.Add(-1)
+					return i, true
+				}).Gen(10_000).Parallel(16).WithExecutor(ex).Sum(func(a, b *int) int { return *a + *b })
+				require.Equal(t, 10_000*9_999/2, res)
+			}()
+		}
+		wg.Wait()
+
+		// 20 calling goroutines and 3 goroutines of the pool at most
+		require.LessOrEqual(t, maxRunning.Load(), int64(23))
+		m := ex.Metrics()
+		require.Equal(t, 3, m.MaxWorkers)
+		require.LessOrEqual(t, m.Workers, 3)
+		require.Equal(t, int64(20*15), m.Submitted)
+	})
+
+	t.Run("jobs are taken in turn", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(0)
+		a := &job{pending: 3}
+		b := &job{pending: 1}
+		c := &job{pending: 2}
+		ex.jobs = []*job{a, b, c}
+
+		var order []*job
+		for len(ex.jobs) != 0 {
+			j, _ := ex.take()
+			order = append(order, j)
+		}
+		require.Equal(t, []*job{a, b, c, a, c, a}, order)
+	})
+
+	t.Run("not started workers are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(1)
+		defer ex.Close()
+
+		// the pool goroutine is busy until the pipe is evaluated by the calling one
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go ex.run(2, func(w int) {
+			if w == 0 {
+				<-started
+				return
+			}
+			close(started)
+			<-release
+		})
+		<-started
+
+		res := Slice([]int{1, 2, 3}).Parallel(4).WithExecutor(ex).Do()
+		require.Equal(t, []int{1, 2, 3}, res)
+		require.Equal(t, int64(3), ex.Metrics().Dropped)
+		close(release)
+	})
+
+	t.Run("nested pipes", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(1)
+		defer ex.Close()
+
+		res := Func(func(i int) (int, bool) {
+			return Func(func(j int) (int, bool) { return j, true }).
+				Gen(i).Parallel(4).WithExecutor(ex).Count(), true
+		}).Gen(100).Parallel(4).WithExecutor(ex).Do()
+		for i := range res {
+			require.Equal(t, i, res[i])
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(4)
+		ex.Close()
+
+		res := Slice([]int{1, 2, 3}).Parallel(4).WithExecutor(ex).Do()
+		require.Equal(t, []int{1, 2, 3}, res)
+		require.Equal(t, int64(0), ex.Metrics().Submitted)
+	})
+
+	t.Run("sorts", func(t *testing.T) {
+		t.Parallel()
+
+		a := make([]int, 100_000)
+		for i := range a {
+			a[i] = len(a) - i
+		}
+		key := func(x *int) uint64 { return uint64(*x) }
+		str := func(x *int) string { return strconv.Itoa(*x) }
+		for _, size := range []int{0, 4} {
+			ex := NewExecutor(size)
+			res := Slice(a).Parallel(4).WithExecutor(ex).SortByUint(key).Do()
+			require.Len(t, res, len(a))
+			require.True(t, sort.IntsAreSorted(res))
+			res = Slice(a).Parallel(4).WithExecutor(ex).SortByString(str).Do()
+			require.Len(t, res, len(a))
+			require.True(t, sort.SliceIsSorted(res, func(i, j int) bool { return str(&res[i]) < str(&res[j]) }))
+			if size != 0 {
+				require.NotZero(t, ex.Metrics().Submitted)
+			}
+			ex.Close()
+		}
+	})
+
+	t.Run("spawn", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(0)
+		called := 0
+		wait := ex.spawn(func() { called++ })
+		require.Zero(t, called)
+		wait()
+		require.Equal(t, 1, called)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, DefaultExecutorSize, DefaultExecutor().Metrics().MaxWorkers)
+		p := Slice([]int{1})
+		require.Same(t, DefaultExecutor(), p.Executor())
+		ex := NewExecutor(1)
+		require.Same(t, ex, p.WithExecutor(ex).Map(func(x int) int { return x }).Executor())
+	})
+}
@@ -0,0 +1,297 @@
+package internalpipe
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// fileRangeSize is the size of the byte ranges a file of lines is split into.
+	fileRangeSize = 1 << 20
+	// lineSearchStep is the amount of bytes read at once looking for the end of a line.
+	lineSearchStep = 1 << 12
+)
+
+// FileLines creates a pipe of the lines of the file at path in no particular order, the length is unknown.
+// The file is split into byte ranges aligned to the line boundaries read and parsed in parallel
+// by the goroutines evaluating the pipe, the lines of a range are given out once it's parsed, so the ranges
+// come in no particular order. See fileReader for the reading and the closing of the file.
+func FileLines(path string) Pipe[string] {
+	return fileLines(path, false)
+}
+
+// FileLinesOrdered creates a pipe of the lines of the file at path in the order of the file, the length is unknown.
+// The ranges of the file are read and parsed in parallel the way FileLines does and given out in order.
+func FileLinesOrdered(path string) Pipe[string] {
+	return fileLines(path, true)
+}
+
+func fileLines(path string, ordered bool) Pipe[string] {
+	y := newRelay()
+	r := &fileReader{path: path, y: y, ordered: ordered}
+	return sequenceWith(y, r.next, seqHooks{start: r.prepare, release: r.stop})
+}
+
+// fileReader reads the lines of a file by its byte ranges. The file is opened on the first line requested.
+// A goroutine evaluating the pipe right after the source parses the next range itself once there is no parsed one,
+// the rest of them are started on the executor of the pipe as range readers parsing the ranges in parallel
+// at most 2*workers ranges ahead of the pipe. The file is closed at its end or once the terminal operation is over.
+// The errors are yeeted to the Yeti of the pipe, an unread range is skipped.
+type fileReader struct {
+	path    string
+	y       yeti
+	ordered bool
+	ex      *Executor
+	workers int
+
+	once    sync.Once
+	closing sync.Once
+	f       *os.File
+	size    int64
+	// slots bounds the amount of the ranges claimed by the range readers but not given out yet
+	slots   chan struct{}
+	parsed  chan parsedRange
+	stopped chan struct{}
+	// wait waits for the range readers to return
+	wait func()
+
+	// mx guards the claiming of the ranges
+	mx   sync.Mutex
+	off  int64
+	last int
+
+	// the state of the pipe side, it's read one line at a time
+	pending  map[int]parsedRange
+	head     int
+	received int
+	lines    []string
+}
+
+// parsedRange is the lines of the range k of a file.
+type parsedRange struct {
+	k     int
+	lines []string
+	// slot is set if the range holds a slot of a range reader
+	slot bool
+}
+
+// prepare takes the executor and the amount of the goroutines of the terminal operation reading the file.
+func (r *fileReader) prepare(e evaluation) {
+	r.ex, r.workers = e.ex, e.workers
+}
+
+// start opens the file and starts the range readers.
+func (r *fileReader) start() {
+	r.workers = max(r.workers, 1)
+	r.slots = make(chan struct{}, 2*r.workers)
+	r.parsed = make(chan parsedRange, 2*r.workers)
+	r.stopped = make(chan struct{})
+	r.pending = make(map[int]parsedRange)
+	r.wait = noop
+
+	var err error
+	if r.f, err = os.Open(r.path); err == nil {
+		var info os.FileInfo
+		if info, err = r.f.Stat(); err == nil {
+			r.size = info.Size()
+		}
+	}
+	if err != nil {
+		r.y.Yeet(err)
+		r.size = 0
+		r.close()
+		return
+	}
+	if r.ex != nil {
+		r.wait = r.ex.start(r.workers-1, func(int) {
+			r.read()
+		})
+	}
+}
+
+// read parses the ranges one by one while there is a room for them.
+func (r *fileReader) read() {
+	for {
+		select {
+		case r.slots <- struct{}{}:
+		case <-r.stopped:
+			return
+		}
+		k, off, end, ok := r.claim()
+		if !ok {
+			<-r.slots
+			return
+		}
+
+		pr := r.parse(k, off, end)
+		pr.slot = true
+		select {
+		case r.parsed <- pr:
+		case <-r.stopped:
+			return
+		}
+	}
+}
+
+// parse reads and splits the range k of [off, end).
+func (r *fileReader) parse(k int, off, end int64) parsedRange {
+	data := make([]byte, end-off)
+	n, err := r.f.ReadAt(data, off)
+	if err != nil && !(err == io.EOF && n == len(data)) {
+		r.y.Yeet(err)
+		data = nil
+	}
+	return parsedRange{k: k, lines: splitLines(string(data))}
+}
+
+// claim takes the next range of whole lines, it returns false at the end of the file.
+func (r *fileReader) claim() (int, int64, int64, bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.off >= r.size {
+		return 0, 0, 0, false
+	}
+	end, err := lineEnd(r.f, min(r.off+fileRangeSize, r.size), r.size)
+	if err != nil {
+		r.y.Yeet(err)
+		r.off = r.size
+		return 0, 0, 0, false
+	}
+	k, off := r.last, r.off
+	r.last++
+	r.off = end
+	return k, off, end, true
+}
+
+// next returns the next line, it returns false at the end of the file.
+func (r *fileReader) next() (string, bool) {
+	r.once.Do(r.start)
+	for len(r.lines) == 0 {
+		if !r.nextRange() {
+			r.close()
+			return "", false
+		}
+	}
+	line := r.lines[0]
+	r.lines = r.lines[1:]
+	return line, true
+}
+
+// nextRange takes the lines of the next range parsed, in the order of the file if it's ordered.
+func (r *fileReader) nextRange() bool {
+	for {
+		if pr, ok := r.pending[r.head]; ok && r.ordered {
+			delete(r.pending, r.head)
+			r.head++
+			r.give(pr)
+			return true
+		}
+		pr, ok := r.receive()
+		if !ok {
+			return false
+		}
+		if !r.ordered {
+			r.give(pr)
+			return true
+		}
+		r.pending[pr.k] = pr
+	}
+}
+
+// receive returns a range parsed by a range reader or, if there is none, parses the next range right away
+// unless 2*workers ranges wait for the one to give out first. It returns false once all the ranges are received.
+func (r *fileReader) receive() (parsedRange, bool) {
+	select {
+	case pr := <-r.parsed:
+		r.received++
+		return pr, true
+	default:
+	}
+
+	if len(r.pending) < 2*r.workers {
+		if k, off, end, ok := r.claim(); ok {
+			r.received++
+			return r.parse(k, off, end), true
+		}
+	}
+	r.mx.Lock()
+	claimed := r.last
+	r.mx.Unlock()
+	if r.received == claimed {
+		return parsedRange{}, false
+	}
+	pr := <-r.parsed
+	r.received++
+	return pr, true
+}
+
+// give makes the lines of the range the next ones to read freeing its slot.
+func (r *fileReader) give(pr parsedRange) {
+	r.lines = pr.lines
+	if pr.slot {
+		<-r.slots
+	}
+}
+
+// stop stops the range readers if they are started, waits for them to return and closes the file.
+func (r *fileReader) stop() {
+	r.once.Do(noop)
+	if r.stopped == nil {
+		return
+	}
+	close(r.stopped)
+	r.wait()
+	r.close()
+}
+
+// close closes the file once.
+func (r *fileReader) close() {
+	r.closing.Do(func() {
+		if r.f == nil {
+			return
+		}
+		if err := r.f.Close(); err != nil {
+			r.y.Yeet(err)
+		}
+	})
+}
+
+// lineEnd returns the position right after the end of the line holding the byte pos-1 or size if there is none.
+func lineEnd(r io.ReaderAt, pos, size int64) (int64, error) {
+	buf := make([]byte, lineSearchStep)
+	for pos < size && pos > 0 {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), size-pos+1)], pos-1)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i), nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		pos += int64(n)
+	}
+	return min(pos, size), nil
+}
+
+// dropCR drops a terminal \r from a line the way bufio.ScanLines does.
+func dropCR(s string) string {
+	return strings.TrimSuffix(s, "\r")
+}
+
+// splitLines splits data into the lines sharing its memory.
+func splitLines(data string) []string {
+	lines := make([]string, 0, strings.Count(data, "\n")+1)
+	for data != "" {
+		i := strings.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, dropCR(data))
+			break
+		}
+		lines = append(lines, dropCR(data[:i]))
+		data = data[i+1:]
+	}
+	return lines
+}
@@ -0,0 +1,129 @@
+package internalpipe
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeLines writes the lines of different lengths ending with "\n" or "\r\n" into a file spanning several ranges.
+// The last line ends with tail.
+func writeLines(t *testing.T, n int, tail string) (string, []string) {
+	text, exp := fixture(n, func(i int) string {
+		return strconv.Itoa(i) + strings.Repeat("x", i%300)
+	}, func(s string) string {
+		if len(s)%2 == 0 {
+			return s + "\r"
+		}
+		return s
+	})
+
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.TrimSuffix(text, "\n")+tail), 0o600))
+	return path, exp
+}
+
+func Test_lineEnd(t *testing.T) {
+	t.Parallel()
+
+	r := strings.NewReader("ab\ncd\n\nef")
+	for _, tc := range []struct{ pos, exp int64 }{
+		{1, 3}, {2, 3}, {3, 3}, {4, 6}, {6, 6}, {7, 7}, {8, 9}, {9, 9},
+	} {
+		end, err := lineEnd(r, tc.pos, r.Size())
+		require.NoError(t, err)
+		require.Equal(t, tc.exp, end, "pos %d", tc.pos)
+	}
+}
+
+func Test_FileLines(t *testing.T) {
+	t.Parallel()
+
+	for _, tail := range []string{"", "\n"} {
+		path, exp := writeLines(t, 50_000, tail)
+
+		for _, threads := range []uint16{1, 8} {
+			res := FileLines(path).Parallel(threads).Do()
+			sort.Strings(res)
+			sorted := append([]string(nil), exp...)
+			sort.Strings(sorted)
+			require.Equal(t, sorted, res)
+		}
+	}
+}
+
+func Test_FileLinesOrdered(t *testing.T) {
+	t.Parallel()
+
+	for _, tail := range []string{"", "\n"} {
+		path, exp := writeLines(t, 50_000, tail)
+
+		require.Equal(t, exp, FileLinesOrdered(path).Do())
+		require.Equal(t, exp, FileLinesOrdered(path).Parallel(8).Do())
+		notFirst := func(s *string) bool { return *s != exp[0] }
+		require.Equal(t, exp[1:], FileLinesOrdered(path).Filter(notFirst).Parallel(8).Do())
+		require.Equal(t, exp[:12345], FileLinesOrdered(path).Parallel(8).Take(12345).Do())
+		require.Equal(t, 50_000, FileLinesOrdered(path).Parallel(8).Count())
+	}
+}
+
+func Test_FileLines_error(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing.txt")
+	for _, p := range []Pipe[string]{FileLines(path), FileLinesOrdered(path)} {
+		var handled []error
+		res := p.Yeti(NewYeti()).Snag(func(err error) { handled = append(handled, err) }).Do()
+		require.Empty(t, res)
+		require.Len(t, handled, 1)
+		require.True(t, os.IsNotExist(handled[0]))
+	}
+}
+
+func Test_FileLines_stop(t *testing.T) {
+	t.Parallel()
+
+	path, exp := writeLines(t, 50_000, "")
+	r := &fileReader{path: path, y: newRelay(), ordered: true}
+	p := sequenceWith(r.y.(*relay), r.next, seqHooks{start: r.prepare, release: r.stop})
+	require.Equal(t, exp[:10], p.Parallel(4).Take(10).Do())
+	// the file is closed once the terminal operation is over
+	require.ErrorIs(t, r.f.Close(), os.ErrClosed)
+
+	r = &fileReader{path: path, y: newRelay()}
+	p = sequenceWith(r.y.(*relay), r.next, seqHooks{start: r.prepare, release: r.stop})
+	require.Len(t, p.Do(), len(exp))
+	require.ErrorIs(t, r.f.Close(), os.ErrClosed)
+}
+
+func Test_FileLines_executor(t *testing.T) {
+	t.Parallel()
+
+	path, exp := writeLines(t, 50_000, "")
+
+	// the goroutine evaluating the pipe reads all the ranges if no range reader starts
+	ex := NewExecutor(0)
+	require.Equal(t, exp, FileLinesOrdered(path).Parallel(4).WithExecutor(ex).Do())
+	require.Len(t, FileLines(path).Parallel(4).WithExecutor(ex).Do(), len(exp))
+
+	ex = NewExecutor(4)
+	defer ex.Close()
+	require.Equal(t, exp, FileLinesOrdered(path).WithExecutor(ex).Do())
+	require.Zero(t, ex.Metrics().Submitted)
+	require.Equal(t, exp, FileLinesOrdered(path).Parallel(4).WithExecutor(ex).Do())
+	require.NotZero(t, ex.Metrics().Submitted)
+}
+
+func Test_FileLines_empty(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	require.Equal(t, []string{}, FileLines(path).Parallel(4).Do())
+	require.Equal(t, []string{}, FileLinesOrdered(path).Do())
+}
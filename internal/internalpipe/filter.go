@@ -2,6 +2,16 @@ package internalpipe
 
 // Filter leaves only items with true predicate fn.
 func (p Pipe[T]) Filter(fn func(*T) bool) Pipe[T] {
+	var batchFn BatchFn[T]
+	if p.BatchFn != nil {
+		batchFn = func(lf, rg int, out []T, skipped []bool) {
+			p.BatchFn(lf, rg, out, skipped)
+			for j := range out[:rg-lf] {
+				skipped[j] = skipped[j] || !fn(&out[j])
+			}
+		}
+	}
+
 	return Pipe[T]{
 		Fn: func(i int) (*T, bool) {
 			if obj, skipped := p.Fn(i); !skipped {
@@ -12,10 +22,17 @@ func (p Pipe[T]) Filter(fn func(*T) bool) Pipe[T] {
 			}
 			return nil, true
 		},
+		BatchFn:       batchFn,
 		Len:           p.Len,
 		ValLim:        p.ValLim,
 		GoroutinesCnt: p.GoroutinesCnt,
 
-		y: p.y,
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+		stages:      p.stages,
 	}
 }
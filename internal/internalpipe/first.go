@@ -1,24 +1,26 @@
 package internalpipe
 
 import (
-	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 )
 
 func (p Pipe[T]) First() *T {
-	limit := p.limit()
+	defer p.open()()
+
+	limit := p.searchLimit()
 	if p.GoroutinesCnt == 1 {
-		return firstSingleThread(limit, p.Fn)
+		return firstSingleThread(&p, limit)
 	}
-	return first(limit, p.GoroutinesCnt, p.Fn)
+	return first(&p, limit)
 }
 
-func firstSingleThread[T any](limit int, fn func(i int) (*T, bool)) *T {
+func firstSingleThread[T any](p *Pipe[T], limit int) *T {
 	var obj *T
 	var skipped bool
-	for i := 0; i < limit; i++ {
-		obj, skipped = fn(i)
+	for i := 0; i < limit && !p.ended(i); i++ {
+		obj, skipped = p.Fn(i)
 		if !skipped {
 			return obj
 		}
@@ -26,117 +28,44 @@ func firstSingleThread[T any](limit int, fn func(i int) (*T, bool)) *T {
 	return nil
 }
 
-type firstResult[T any] struct {
-	val            *T
-	step           int
-	totalSteps     int
-	zeroStepBorder int
-	mx             *sync.Mutex
-	ctx            context.Context
-	cancel         func()
-	done           map[int]struct{}
-
-	resForSure chan *T
-}
-
-func newFirstResult[T any](totalSteps int) *firstResult[T] {
-	ctx, cancel := context.WithCancel(context.Background())
-	return &firstResult[T]{
-		step:       math.MaxInt,
-		totalSteps: totalSteps,
-		mx:         &sync.Mutex{},
-		ctx:        ctx,
-		cancel:     cancel,
-		done:       make(map[int]struct{}, totalSteps),
-		resForSure: make(chan *T),
+// first looks for the not skipped element with the lowest index.
+// Each chunk is evaluated until the first found element or the lowest index found so far.
+func first[T any](p *Pipe[T], limit int) *T {
+	if limit == 0 {
+		return nil
 	}
-}
-
-func (r *firstResult[T]) setVal(val *T, step int) {
-	r.mx.Lock()
-	defer r.mx.Unlock()
 
-	if step == r.zeroStepBorder {
-		r.resForSure <- val
-		r.cancel()
-		return
+	var (
+		res  *T
+		mx   sync.Mutex
+		best atomic.Int64
+	)
+	best.Store(math.MaxInt64)
+
+	// a pipe with no length is scheduled as an unbounded one to keep its chunks in order
+	schedLimit := limit
+	if !p.lenSet() {
+		schedLimit = unboundedLimit
 	}
-	if step < r.step {
-		r.val = val
-		r.step = step
-	}
-}
-
-func (r *firstResult[T]) stepDone(step int) {
-	r.mx.Lock()
-	defer r.mx.Unlock()
-
-	r.done[step] = struct{}{}
-
-	// need to move r.zeroStepBorder up
-	if step == r.zeroStepBorder {
-		ok := true
-		for ok {
-			r.zeroStepBorder++
-			if r.zeroStepBorder == r.step {
-				r.resForSure <- r.val
-				r.cancel()
-				return
-			}
-
-			_, ok = r.done[r.zeroStepBorder]
+	p.scheduleRange(0, schedLimit, func(_, lf, rg int) bool {
+		if int64(lf) >= best.Load() || lf >= limit || p.ended(lf) {
+			// the chunks of an unbounded or staged pipe are given out in order, so all the rest are even further
+			return schedLimit != unboundedLimit && len(p.stages) == 0
 		}
-	}
-
-	if r.zeroStepBorder >= r.totalSteps {
-		r.resForSure <- nil
-		r.cancel()
-	}
-}
-
-func first[T any](limit, grtCnt int, fn func(i int) (*T, bool)) (f *T) {
-	if limit == 0 {
-		return
-	}
-	step := max(divUp(limit, grtCnt), 1)
-	tickets := genTickets(grtCnt)
-
-	res := newFirstResult[T](grtCnt)
-
-	stepCnt := 0
-	for i := 0; i >= 0 && i < limit; i += step {
-		<-tickets
-		go func(lf, rg, stepCnt int) {
-			defer func() {
-				tickets <- struct{}{}
-			}()
-
-			done := res.ctx.Done()
-			for j := lf; j < rg; j++ {
-				// FIXME: this code is ugly but saves about 30% of time on locks
-				if j%2 != 0 {
-					val, skipped := fn(j)
-					if !skipped {
-						res.setVal(val, stepCnt)
-						return
-					}
-				} else {
-					select {
-					case <-done:
-						return
-					default:
-						val, skipped := fn(j)
-						if !skipped {
-							res.setVal(val, stepCnt)
-							return
-						}
-					}
+		for j := lf; j < min(rg, limit) && int64(j) < best.Load() && !p.ended(j); j++ {
+			if obj, skipped := p.Fn(j); !skipped {
+				mx.Lock()
+				if int64(j) < best.Load() {
+					best.Store(int64(j))
+					res = obj
 				}
+				mx.Unlock()
+				p.drop(j + 1)
+				break
 			}
-			res.stepDone(stepCnt)
-		}(i, i+step, stepCnt)
-		stepCnt++
-	}
+		}
+		return true
+	})
 
-	return <-res.resForSure
+	return res
 }
@@ -0,0 +1,26 @@
+package internalpipe
+
+import "strings"
+
+// fixture returns the n elements made of their indexes by elem along with the text of them written by line.
+func fixture[T any](n int, elem func(i int) T, line func(T) string) (string, []T) {
+	exp := make([]T, n)
+	for i := range exp {
+		exp[i] = elem(i)
+	}
+	return joinLines(exp, line), exp
+}
+
+// joinLines returns the elements of a written by line, each one followed by "\n".
+func joinLines[T any](a []T, line func(T) string) string {
+	var sb strings.Builder
+	for _, x := range a {
+		sb.WriteString(line(x))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func itself[T any](x T) T {
+	return x
+}
@@ -0,0 +1,128 @@
+package internalpipe
+
+import "sort"
+
+type foldPart[A any] struct {
+	lf, rg int
+	acc    A
+}
+
+// Fold evaluates the pipe accumulating each not skipped element with acc along with its index.
+// The pipe is evaluated in p.GoroutinesCnt goroutines, each consecutive range of elements
+// evaluated by a goroutine is accumulated into its own value made by init.
+// The values are combined with combine in the order of the ranges, so combine(a, b) always gets a
+// made of the elements with lower indexes than b. A pipe with no length is streamed: each chunk of the stream
+// is accumulated by the goroutine evaluating it and the values are combined as the chunks are merged in order.
+// If the context of the pipe is done before any part is evaluated, the result is made by init
+// and the error of the context is yeeted to the Yeti of the pipe.
+func Fold[T, A any](p Pipe[T], init func() A, acc func(A, int, *T) A, combine func(A, A) A) A {
+	if p.y != nil {
+		defer p.y.Handle()
+	}
+	defer p.open()()
+
+	if !p.lenSet() {
+		return foldStream(&p, init, acc, combine)
+	}
+
+	limit := p.limit()
+	if p.GoroutinesCnt == 1 || limit < 2 {
+		return foldRange(p.Fn, 0, limit, init(), acc)
+	}
+
+	workerParts := make([][]foldPart[A], p.GoroutinesCnt)
+	p.schedule(func(w, lf, rg int) bool {
+		parts := workerParts[w]
+		if last := len(parts) - 1; last >= 0 && parts[last].rg == lf {
+			parts[last].acc = foldRange(p.Fn, lf, rg, parts[last].acc, acc)
+			parts[last].rg = rg
+			return true
+		}
+		workerParts[w] = append(parts, foldPart[A]{lf: lf, rg: rg, acc: foldRange(p.Fn, lf, rg, init(), acc)})
+		return true
+	})
+
+	parts := make([]foldPart[A], 0, p.GoroutinesCnt)
+	for _, wp := range workerParts {
+		parts = append(parts, wp...)
+	}
+	if len(parts) == 0 {
+		return init()
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].lf < parts[j].lf })
+
+	res := parts[0].acc
+	for _, part := range parts[1:] {
+		res = combine(res, part.acc)
+	}
+	return res
+}
+
+func foldRange[T, A any](fn GeneratorFn[T], lf, rg int, res A, acc func(A, int, *T) A) A {
+	for i := lf; i < rg; i++ {
+		if obj, skipped := fn(i); !skipped {
+			res = acc(res, i, obj)
+		}
+	}
+	return res
+}
+
+// foldElem is an element found in a chunk of a stream along with its index.
+type foldElem[T any] struct {
+	i   int
+	obj *T
+}
+
+// foldStream accumulates the not skipped elements of a pipe with no length
+// until p.ValLim of them are found (or all of them if it's not set) or the source of the pipe ends.
+// The chunks of the stream are accumulated in parallel, the last chunk merged is accumulated anew
+// up to the limit, so the elements of a chunk are kept only if the limit is set.
+func foldStream[T, A any](p *Pipe[T], init func() A, acc func(A, int, *T) A, combine func(A, A) A) A {
+	limit := p.limit()
+	if p.GoroutinesCnt == 1 && !p.auto && len(p.stages) == 0 {
+		res := init()
+		for i, found := 0, 0; found < limit && i < unboundedLimit && !p.ended(i) && !p.canceled(); i++ {
+			if obj, skipped := p.Fn(i); !skipped {
+				res = acc(res, i, obj)
+				found++
+			}
+		}
+		return res
+	}
+
+	var (
+		res A
+		set bool
+	)
+	bounded := limit != unboundedLimit
+	m := newStreamMerge[T](limit, false, nil)
+	fn, src := p.Fn, p.src
+	p.scheduleRange(0, unboundedLimit, m.body(src, func(lf, rg int) *streamChunk[T] {
+		part := init()
+		var found []foldElem[T]
+		c := m.scan(&streamChunk[T]{lf: lf, rg: rg}, fn, src, func(i int, obj *T) {
+			part = acc(part, i, obj)
+			if bounded {
+				found = append(found, foldElem[T]{i: i, obj: obj})
+			}
+		})
+		c.apply = func(take int) {
+			if take < c.cnt {
+				part = init()
+				for _, e := range found[:take] {
+					part = acc(part, e.i, e.obj)
+				}
+			}
+			if set {
+				res = combine(res, part)
+				return
+			}
+			res, set = part, true
+		}
+		return c
+	}))
+	if !set {
+		return init()
+	}
+	return res
+}
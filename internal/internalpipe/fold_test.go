@@ -0,0 +1,84 @@
+package internalpipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Fold(t *testing.T) {
+	t.Parallel()
+
+	collect := func(p Pipe[int]) []int {
+		return Fold(
+			p,
+			func() []int { return nil },
+			func(acc []int, i int, x *int) []int { return append(acc, i) },
+			func(a, b []int) []int { return append(a, b...) },
+		)
+	}
+
+	t.Run("keeps the order of parts", func(t *testing.T) {
+		t.Parallel()
+
+		p := Func(func(i int) (int, bool) { return i, i%3 != 0 }).Gen(10_000)
+		exp := make([]int, 0, 10_000)
+		for i := 0; i < 10_000; i++ {
+			if i%3 != 0 {
+				exp = append(exp, i)
+			}
+		}
+		require.Equal(t, exp, collect(p))
+		require.Equal(t, exp, collect(p.Parallel(7)))
+	})
+
+	t.Run("take", func(t *testing.T) {
+		t.Parallel()
+
+		p := Func(func(i int) (int, bool) { return i, i%2 == 0 }).Take(4).Parallel(4)
+		require.Equal(t, []int{0, 2, 4, 6}, collect(p))
+	})
+
+	t.Run("no length in parallel", func(t *testing.T) {
+		t.Parallel()
+
+		var c concurrency
+		p := Func(func(i int) (int, bool) {
+			c.enter()
+			defer c.leave()
+			time.Sleep(100 * time.Microsecond)
+			return i, i%5 == 0
+		}).Take(100)
+
+		seq := collect(p)
+		require.Len(t, seq, 100)
+		require.Equal(t, 495, seq[len(seq)-1])
+		require.Equal(t, int64(1), c.max.Load())
+		require.Equal(t, seq, collect(p.Parallel(4)))
+		require.Greater(t, c.max.Load(), int64(1))
+
+		src := finite(10_000)
+		sum := Fold(src.Parallel(4), func() int { return 0 }, func(a, _ int, x *int) int { return a + *x }, func(a, b int) int { return a + b })
+		require.Equal(t, 10_000*9_999/2, sum)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, collect(Slice([]int{}).Parallel(4)))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var yeeted []error
+		y := NewYeti()
+		y.Snag(func(err error) { yeeted = append(yeeted, err) })
+		require.Empty(t, collect(Func(func(i int) (int, bool) { return i, true }).Gen(10_000).Parallel(4).WithContext(ctx).Yeti(y)))
+		require.Equal(t, []error{context.Canceled}, yeeted)
+	})
+}
@@ -0,0 +1,216 @@
+package internalpipe
+
+import (
+	"context"
+	"sync"
+)
+
+// forEachChunk is the amount of elements ForEachOrdered evaluates at once.
+const forEachChunk = 1 << 8
+
+// ErrMode sets how ForEachErr treats the errors of its function.
+type ErrMode uint8
+
+const (
+	// ContinueOnError yeets each error to the Yeti of the pipe and goes on.
+	ContinueOnError ErrMode = iota
+	// FailFast yeets the first error to the Yeti of the pipe and stops the evaluation.
+	FailFast
+)
+
+// ForEach calls fn for each element of the pipe in the goroutines evaluating it, the order of the calls is undefined.
+// No result is gathered. It stops once the context of the pipe is done.
+// A pipe with no length but a limit set passes its elements to fn one at a time, so fn is never called
+// on the elements past the limit.
+func (p Pipe[T]) ForEach(fn func(*T)) {
+	p.forEach(func(x *T) error {
+		fn(x)
+		return nil
+	}, ContinueOnError)
+}
+
+// ForEachErr calls fn the way ForEach does, the errors of fn are treated the way mode sets.
+// It returns the first error of fn or the error of the context of the pipe if it's done.
+func (p Pipe[T]) ForEachErr(fn func(*T) error, mode ErrMode) error {
+	return p.forEach(fn, mode)
+}
+
+func (p *Pipe[T]) forEach(fn func(*T) error, mode ErrMode) error {
+	if p.y != nil {
+		defer p.y.Handle()
+	}
+	defer p.open()()
+
+	parent := p.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	eval := p.WithContext(ctx)
+
+	var (
+		first error
+		once  sync.Once
+	)
+	call := func(x *T) bool {
+		if eval.canceled() {
+			return false
+		}
+		err := fn(x)
+		if err == nil {
+			return true
+		}
+		once.Do(func() { first = err })
+		if p.y != nil {
+			p.y.Yeet(err)
+		}
+		if mode == FailFast {
+			cancel()
+			p.drop(0)
+			return false
+		}
+		return true
+	}
+
+	switch {
+	case eval.lenSet():
+		each := eval.eachIn()
+		eval.schedule(func(_, lf, rg int) bool {
+			return each(lf, rg, call)
+		})
+	case eval.limitSet():
+		eval.stream(false, func(vals []T) {
+			for i := range vals {
+				if !call(&vals[i]) {
+					return
+				}
+			}
+		})
+	default:
+		calling := Derive(eval, func(i int) (*T, bool) {
+			obj, skipped := eval.Fn(i)
+			if skipped || !call(obj) {
+				return nil, true
+			}
+			return obj, false
+		})
+		calling.stream(false, nil)
+	}
+
+	if first == nil && p.canceled() {
+		return p.ctx.Err()
+	}
+	return first
+}
+
+// ForEachOrdered calls fn for each element of the pipe one at a time in the order of the pipe.
+// The elements are evaluated by chunks in the goroutines evaluating the pipe and passed to fn in a single one,
+// at most a couple of chunks per goroutine wait to be passed. It stops once the context of the pipe is done.
+func (p Pipe[T]) ForEachOrdered(fn func(*T)) {
+	if p.y != nil {
+		defer p.y.Handle()
+	}
+	defer p.open()()
+
+	if !p.lenSet() {
+		p.stream(false, func(vals []T) {
+			for i := 0; i < len(vals) && !p.canceled(); i++ {
+				fn(&vals[i])
+			}
+		})
+		return
+	}
+
+	chunks := sync.Pool{
+		New: func() any {
+			return new([]T)
+		},
+	}
+	workers := max(p.GoroutinesCnt, 1)
+	seq := newSequencer(2*workers*forEachChunk, func(vals *[]T) error {
+		for i := 0; i < len(*vals); i++ {
+			if p.canceled() {
+				return p.ctx.Err()
+			}
+			fn(&(*vals)[i])
+		}
+		*vals = (*vals)[:0]
+		chunks.Put(vals)
+		return nil
+	})
+	each := p.eachIn()
+	pl := plan{workers: workers, chunk: forEachChunk}
+	schedule(p.Executor(), 0, p.Len, pl, cancelable(p.ctx, func(_, lf, rg int) bool {
+		if !seq.wait(lf) {
+			return false
+		}
+		vals := chunks.Get().(*[]T)
+		each(lf, rg, func(x *T) bool {
+			*vals = append(*vals, *x)
+			return true
+		})
+		return seq.submit(lf, rg, vals, nil)
+	}))
+	_ = seq.close()
+}
+
+// eachIn returns the function calling fn for the elements of [lf, rg) of the pipe which are not skipped
+// until fn returns false, it returns false if fn has stopped it. The pipe must have its length set.
+// The elements are evaluated by blocks if the pipe has ValFn or BatchFn.
+func (p *Pipe[T]) eachIn() func(lf, rg int, fn func(*T) bool) bool {
+	fill := p.blockFiller()
+	if fill == nil {
+		gen := p.Fn
+		return func(lf, rg int, fn func(*T) bool) bool {
+			for j := lf; j < rg; j++ {
+				if obj, skipped := gen(j); !skipped && !fn(obj) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	blocks := sync.Pool{
+		New: func() any {
+			return new([batchBlock]T)
+		},
+	}
+	return func(lf, rg int, fn func(*T) bool) bool {
+		out := blocks.Get().(*[batchBlock]T)
+		skipped := skippedPool.Get().(*[batchBlock]bool)
+		defer func() {
+			blocks.Put(out)
+			skippedPool.Put(skipped)
+		}()
+
+		for ; lf < rg; lf += batchBlock {
+			e := min(lf+batchBlock, rg)
+			fill(lf, e, out[:e-lf], skipped[:e-lf])
+			for k := 0; k < e-lf; k++ {
+				if !skipped[k] && !fn(&out[k]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
+// blockFiller returns the BatchFn of the pipe made of ValFn if it has one, nil if it has neither of them.
+func (p *Pipe[T]) blockFiller() BatchFn[T] {
+	switch {
+	case p.ValFn != nil:
+		valFn := p.ValFn
+		return func(lf, rg int, out []T, skipped []bool) {
+			unskip(skipped[:rg-lf])
+			for j := lf; j < rg; j++ {
+				out[j-lf] = valFn(j)
+			}
+		}
+	case p.BatchFn != nil:
+		return p.BatchFn
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package internalpipe
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ForEach(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 100_000)
+	for i := range a {
+		a[i] = i
+	}
+	even := func(x *int) bool { return *x%2 == 0 }
+	var evens []int
+	for _, x := range a {
+		if even(&x) {
+			evens = append(evens, x)
+		}
+	}
+
+	collect := func(p Pipe[int]) []int {
+		var mx sync.Mutex
+		var res []int
+		p.ForEach(func(x *int) {
+			mx.Lock()
+			res = append(res, *x)
+			mx.Unlock()
+		})
+		sort.Ints(res)
+		return res
+	}
+
+	t.Run("single thread", func(t *testing.T) {
+		require.Equal(t, a, collect(Slice(a)))
+	})
+	t.Run("threads", func(t *testing.T) {
+		require.Equal(t, a, collect(Slice(a).Parallel(7)))
+	})
+	t.Run("values", func(t *testing.T) {
+		res := collect(Slice(a).Map(func(x int) int { return x * 2 }).Parallel(5))
+		for i := range res {
+			require.Equal(t, 2*i, res[i])
+		}
+	})
+	t.Run("filtered", func(t *testing.T) {
+		require.Equal(t, evens, collect(Slice(a).Filter(even).Parallel(6)))
+		require.Equal(t, evens, collect(Func(func(i int) (int, bool) {
+			return i, i%2 == 0
+		}).Gen(len(a)).Parallel(3)))
+	})
+	t.Run("stages", func(t *testing.T) {
+		require.Equal(t, evens, collect(Slice(a).Parallel(2).Filter(even).Parallel(5)))
+	})
+	t.Run("no length", func(t *testing.T) {
+		require.Equal(t, a, collect(finite(len(a)).Parallel(4)))
+	})
+	t.Run("no length with limit", func(t *testing.T) {
+		require.Equal(t, evens[:1000], collect(Func(func(i int) (int, bool) {
+			return i, i%2 == 0
+		}).Parallel(4).Take(1000)))
+	})
+}
+
+func Test_ForEachOrdered(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 100_000)
+	for i := range a {
+		a[i] = i
+	}
+	collect := func(p Pipe[int]) []int {
+		var res []int
+		p.ForEachOrdered(func(x *int) {
+			res = append(res, *x)
+		})
+		return res
+	}
+
+	require.Equal(t, a, collect(Slice(a)))
+	require.Equal(t, a, collect(Slice(a).Parallel(7)))
+	require.Empty(t, collect(Slice(a[:0]).Parallel(7)))
+	require.Equal(t, a[1:], collect(Slice(a).Filter(func(x *int) bool { return *x != 0 }).Parallel(3)))
+	require.Equal(t, a, collect(Slice(a).Parallel(2).Map(func(x int) int { return x }).Parallel(5)))
+	require.Equal(t, a[:1000], collect(Func(func(i int) (int, bool) {
+		return i, true
+	}).Parallel(4).Take(1000)))
+	require.Equal(t, a, collect(finite(len(a)).Parallel(4)))
+}
+
+func Test_ForEachErr(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = i
+	}
+	errOdd := errors.New("odd")
+	failOdd := func(cnt *atomic.Int64) func(*int) error {
+		return func(x *int) error {
+			cnt.Add(1)
+			if *x%2 == 1 {
+				return errOdd
+			}
+			return nil
+		}
+	}
+
+	t.Run("collect", func(t *testing.T) {
+		y := NewYeti()
+		var errs atomic.Int64
+		y.Snag(func(err error) {
+			require.ErrorIs(t, err, errOdd)
+			errs.Add(1)
+		})
+		var cnt atomic.Int64
+		err := Slice(a).Parallel(4).Yeti(y).ForEachErr(failOdd(&cnt), ContinueOnError)
+		require.ErrorIs(t, err, errOdd)
+		require.Equal(t, int64(len(a)), cnt.Load())
+		require.Equal(t, int64(len(a)/2), errs.Load())
+	})
+	t.Run("fail fast", func(t *testing.T) {
+		var cnt atomic.Int64
+		err := Slice(a).Parallel(4).ForEachErr(failOdd(&cnt), FailFast)
+		require.ErrorIs(t, err, errOdd)
+		require.Less(t, cnt.Load(), int64(len(a)))
+	})
+	t.Run("fail fast no length", func(t *testing.T) {
+		var cnt atomic.Int64
+		err := Func(func(i int) (int, bool) {
+			return i, true
+		}).Parallel(4).ForEachErr(failOdd(&cnt), FailFast)
+		require.ErrorIs(t, err, errOdd)
+	})
+	t.Run("no errors", func(t *testing.T) {
+		var cnt atomic.Int64
+		err := Slice(a).Map(func(x int) int { return x * 2 }).Parallel(4).ForEachErr(failOdd(&cnt), FailFast)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(a)), cnt.Load())
+	})
+}
+
+func Test_ForEach_context(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 100_000)
+	for i := range a {
+		a[i] = i
+	}
+	cancelAt := func(n int64) (context.Context, func(*int)) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var cnt atomic.Int64
+		return ctx, func(*int) {
+			if cnt.Add(1) == n {
+				cancel()
+			}
+		}
+	}
+
+	t.Run("for each", func(t *testing.T) {
+		ctx, fn := cancelAt(100)
+		var cnt atomic.Int64
+		y := NewYeti()
+		var yeeted []error
+		y.Snag(func(err error) { yeeted = append(yeeted, err) })
+		err := Slice(a).Parallel(4).WithContext(ctx).Yeti(y).ForEachErr(func(x *int) error {
+			cnt.Add(1)
+			fn(x)
+			return nil
+		}, ContinueOnError)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, cnt.Load(), int64(len(a)))
+		require.Equal(t, []error{context.Canceled}, yeeted)
+	})
+	t.Run("ordered", func(t *testing.T) {
+		ctx, fn := cancelAt(100)
+		var res []int
+		Slice(a).Parallel(4).WithContext(ctx).ForEachOrdered(func(x *int) {
+			res = append(res, *x)
+			fn(x)
+		})
+		require.Equal(t, a[:100], res)
+	})
+	t.Run("no length", func(t *testing.T) {
+		ctx, fn := cancelAt(100)
+		var cnt atomic.Int64
+		Func(func(i int) (int, bool) {
+			return i, true
+		}).WithContext(ctx).ForEach(func(x *int) {
+			cnt.Add(1)
+			fn(x)
+		})
+		require.GreaterOrEqual(t, cnt.Load(), int64(100))
+	})
+	t.Run("done before", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var cnt atomic.Int64
+		Slice(a).WithContext(ctx).ForEach(func(*int) { cnt.Add(1) })
+		require.Zero(t, cnt.Load())
+
+		y := NewYeti()
+		var yeeted []error
+		y.Snag(func(err error) { yeeted = append(yeeted, err) })
+		Slice(a).Parallel(3).WithContext(ctx).Yeti(y).Do()
+		require.Equal(t, []error{context.Canceled}, yeeted)
+	})
+}
+
+func Test_eachIn(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 1000)
+	for i := range a {
+		a[i] = i
+	}
+	for _, p := range []Pipe[int]{
+		Slice(a).Map(func(x int) int { return x }),
+		Slice(a).Filter(func(*int) bool { return true }),
+		Func(func(i int) (int, bool) { return i, true }).Gen(len(a)),
+	} {
+		cnt := 0
+		all := p.eachIn()(10, 700, func(*int) bool {
+			cnt++
+			return true
+		})
+		require.True(t, all)
+		require.Equal(t, 690, cnt)
+
+		var res []int
+		all = p.eachIn()(10, 700, func(x *int) bool {
+			res = append(res, *x)
+			return *x < 500
+		})
+		require.False(t, all)
+		require.Equal(t, a[10:501], res)
+	}
+}
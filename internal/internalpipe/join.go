@@ -0,0 +1,374 @@
+package internalpipe
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync/atomic"
+)
+
+// joinShards is the amount of the shards of the hash table of HashJoin.
+const joinShards = 1 << 6
+
+// JoinMode sets which pairs of elements are kept by HashJoin.
+type JoinMode uint8
+
+const (
+	// Inner keeps all pairs of left and right elements with equal keys.
+	Inner JoinMode = iota
+	// LeftOuter keeps all pairs of Inner and the left elements with no match paired with nil.
+	LeftOuter
+	// Semi keeps the left elements having a match, each one once, paired with its first match.
+	Semi
+	// Anti keeps the left elements having no match paired with nil.
+	Anti
+)
+
+// HashJoin joins left and right pipes by the equality of their keys.
+// The right pipe is the build side: it is evaluated in parallel right away into a hash table sharded by the keys,
+// the shards are built in parallel keeping the order of the right elements.
+// The left pipe is the probe side and stays lazy. If each left element has a single pair at most,
+// the i-th left element produces the i-th element of the result, so the result keeps the settings of left.
+// Otherwise (or if left has a limit but no length) the result has no length: each terminal operation streams left
+// in parallel and gives out the pairs of its elements in order, the source of the result ends along with left.
+// The result keeps the order of left elements and the order of their matches.
+func HashJoin[L, R any, K comparable, Out any](
+	left Pipe[L],
+	right Pipe[R],
+	lkey func(*L) K,
+	rkey func(*R) K,
+	combine func(*L, *R) Out,
+	mode JoinMode,
+) Pipe[Out] {
+	table := buildJoinTable(right, rkey)
+
+	// the limit of a left pipe with no length is the amount of its own elements, so they are never skipped by probing
+	single := mode == Semi || mode == Anti || table.fanOut <= 1
+	if single && (left.lenSet() || !left.limitSet()) {
+		return Derive(left, func(i int) (*Out, bool) {
+			l, skipped := left.Fn(i)
+			if skipped {
+				return nil, true
+			}
+			rs := table.get(lkey(l))
+
+			var res Out
+			switch {
+			case (mode == Inner || mode == LeftOuter || mode == Semi) && len(rs) != 0:
+				res = combine(l, &rs[0])
+			case mode == LeftOuter || mode == Anti && len(rs) == 0:
+				res = combine(l, nil)
+			default:
+				return nil, true
+			}
+			return &res, false
+		})
+	}
+
+	pairs := Derive(left, func(i int) (*[]Out, bool) {
+		l, skipped := left.Fn(i)
+		if skipped {
+			return nil, true
+		}
+		rs := table.get(lkey(l))
+
+		var res []Out
+		switch {
+		case mode == Semi && len(rs) != 0:
+			rs = rs[:1]
+			fallthrough
+		case (mode == Inner || mode == LeftOuter) && len(rs) != 0:
+			res = make([]Out, len(rs))
+			for j := range rs {
+				res[j] = combine(l, &rs[j])
+			}
+		case mode == LeftOuter || mode == Anti && len(rs) == 0:
+			res = []Out{combine(l, nil)}
+		}
+		return &res, false
+	})
+	// the matches are read one by one, so the result is set up the way a sequence is: left is probed in parallel
+	src := &flatSource[Out]{batches: pairs}
+	return Pipe[Out]{
+		Fn:            src.get,
+		Len:           notSet,
+		ValLim:        notSet,
+		GoroutinesCnt: defaultParallelWrks,
+
+		y:   left.y,
+		ex:  left.ex,
+		ctx: left.ctx,
+		src: src,
+	}
+}
+
+// joinEntry is a right element along with its key.
+type joinEntry[K comparable, R any] struct {
+	k K
+	r R
+}
+
+// joinPart is the entries of a range of the right pipe split by the shards of their keys.
+type joinPart[K comparable, R any] [joinShards][]joinEntry[K, R]
+
+// joinTable is the hash table of the right elements sharded by the hashes of their keys.
+type joinTable[K comparable, R any] struct {
+	hash   func(K) uint64
+	shards [joinShards]map[K][]R
+	// fanOut is the maximal amount of the elements with the same key
+	fanOut int
+}
+
+// buildJoinTable evaluates p splitting its elements by the shards of their keys,
+// then the shards are built in parallel from the parts in the order of the elements.
+func buildJoinTable[K comparable, R any](p Pipe[R], key func(*R) K) *joinTable[K, R] {
+	t := &joinTable[K, R]{hash: keyHash[K]()}
+	parts := Fold(
+		p,
+		func() []*joinPart[K, R] { return []*joinPart[K, R]{{}} },
+		func(ps []*joinPart[K, R], _ int, x *R) []*joinPart[K, R] {
+			k := key(x)
+			s := t.hash(k) % joinShards
+			ps[0][s] = append(ps[0][s], joinEntry[K, R]{k: k, r: *x})
+			return ps
+		},
+		func(a, b []*joinPart[K, R]) []*joinPart[K, R] {
+			return append(a, b...)
+		},
+	)
+
+	var fanOuts [joinShards]int
+	schedule(p.Executor(), 0, joinShards, plan{workers: max(p.GoroutinesCnt, 1), grain: 1}, func(_, lf, rg int) bool {
+		for s := lf; s < rg; s++ {
+			m := make(map[K][]R)
+			for _, part := range parts {
+				for _, e := range part[s] {
+					rs := append(m[e.k], e.r)
+					m[e.k] = rs
+					fanOuts[s] = max(fanOuts[s], len(rs))
+				}
+				part[s] = nil
+			}
+			t.shards[s] = m
+		}
+		return true
+	})
+	for _, f := range fanOuts {
+		t.fanOut = max(t.fanOut, f)
+	}
+	return t
+}
+
+// get returns the elements with the key k.
+func (t *joinTable[K, R]) get(k K) []R {
+	return t.shards[t.hash(k)%joinShards][k]
+}
+
+// flatSource is the source of the elements of a pipe of batches given out one by one.
+// Each terminal operation streams the batches in order in its own goroutine into a seqSource made for it.
+// The concurrent terminal operations share the elements.
+type flatSource[E any] struct {
+	batches Pipe[[]E]
+	run     atomic.Pointer[flatRun[E]]
+}
+
+// flatRun is the state of a flatSource during a terminal operation.
+type flatRun[E any] struct {
+	*seqSource[E]
+	// from is the lowest index dropped, the element being read for it or past it isn't waited for
+	from    atomic.Int64
+	dropped chan struct{}
+}
+
+func (s *flatSource[E]) open(e evaluation) func() {
+	ch := make(chan []E, max(s.batches.GoroutinesCnt, 1))
+	r := &flatRun[E]{dropped: make(chan struct{}, 1)}
+	r.from.Store(unboundedLimit)
+	var (
+		batch []E
+		next  int64
+	)
+	r.seqSource = newSeqSource(newRelay(), func() (E, bool) {
+		var zero E
+		for len(batch) == 0 {
+			select {
+			case b, ok := <-ch:
+				if !ok {
+					return zero, false
+				}
+				batch = b
+			case <-r.dropped:
+				if next >= r.from.Load() {
+					return zero, false
+				}
+			}
+		}
+		e := batch[0]
+		batch = batch[1:]
+		next++
+		return e, true
+	}, seqHooks{})
+	if !s.run.CompareAndSwap(nil, r) {
+		// the elements are given out by the run of another terminal operation
+		return noop
+	}
+
+	parent := s.batches.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	batches := s.batches
+	// the error of the context of the pipe is yeeted by the terminal operation itself
+	batches.y, batches.ctx = e.y, nil
+	go func() {
+		defer close(done)
+		defer close(ch)
+		defer batches.open()()
+
+		eval := batches.WithContext(ctx)
+		if eval.lenSet() {
+			// the stream of a pipe with the length set ends at its length
+			eval.src, eval.Len = boundAt(eval.Len), notSet
+		}
+		eval.stream(false, func(vals [][]E) {
+			for _, v := range vals {
+				select {
+				case ch <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}()
+	return func() {
+		cancel()
+		<-done
+		s.run.Store(nil)
+	}
+}
+
+func (s *flatSource[E]) ended(i int) bool {
+	r := s.run.Load()
+	return r != nil && r.ended(i)
+}
+
+// drop stops waiting for the batches of the elements from the index i on, so the source ends there.
+func (s *flatSource[E]) drop(i int) {
+	r := s.run.Load()
+	if r == nil {
+		return
+	}
+	for from := r.from.Load(); int64(i) < from && !r.from.CompareAndSwap(from, int64(i)); {
+		from = r.from.Load()
+	}
+	select {
+	case r.dropped <- struct{}{}:
+	default:
+	}
+	r.seqSource.drop(i)
+}
+
+func (s *flatSource[E]) get(i int) (*E, bool) {
+	r := s.run.Load()
+	if r == nil {
+		return nil, true
+	}
+	return r.get(i)
+}
+
+// keyHash returns the function hashing the keys of type K, the equal keys have the same hash.
+// The keys of the builtin integer and string types are hashed directly, the other ones by their reflected values.
+func keyHash[K comparable]() func(K) uint64 {
+	seed := maphash.MakeSeed()
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return func(k K) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			h.WriteString(any(k).(string))
+			return h.Sum64()
+		}
+	case int:
+		return func(k K) uint64 { return mix64(uint64(any(k).(int))) }
+	case int64:
+		return func(k K) uint64 { return mix64(uint64(any(k).(int64))) }
+	case int32:
+		return func(k K) uint64 { return mix64(uint64(any(k).(int32))) }
+	case uint:
+		return func(k K) uint64 { return mix64(uint64(any(k).(uint))) }
+	case uint64:
+		return func(k K) uint64 { return mix64(any(k).(uint64)) }
+	case uint32:
+		return func(k K) uint64 { return mix64(uint64(any(k).(uint32))) }
+	}
+	return func(k K) uint64 {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		hashValue(&h, reflect.ValueOf(&k).Elem())
+		return h.Sum64()
+	}
+}
+
+// mix64 scrambles the bits of x the way splitmix64 does.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// hashValue writes the value of v into h, the equal values are written the same way.
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			// -0 equals 0
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i))
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			hashValue(h, v.Elem())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package internalpipe
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HashJoin(t *testing.T) {
+	t.Parallel()
+
+	id := func(x *int) int { return *x }
+	pair := func(l, r *int) [2]int {
+		if r == nil {
+			return [2]int{*l, -1}
+		}
+		return [2]int{*l, *r}
+	}
+
+	t.Run("fan out", func(t *testing.T) {
+		t.Parallel()
+
+		left := Slice([]int{1, 2, 3})
+		right := Slice([]int{3, 1, 3, 3})
+		p := HashJoin(left, right, id, id, pair, LeftOuter)
+		require.False(t, p.lenSet())
+		exp := [][2]int{{1, 1}, {2, -1}, {3, 3}, {3, 3}, {3, 3}}
+		require.Equal(t, exp, p.Parallel(4).Do())
+		// each terminal operation probes left anew
+		require.Equal(t, exp, p.Do())
+		require.Equal(t, 5, p.Count())
+		require.Equal(t, [][2]int{{1, 1}, {3, 3}}, HashJoin(left, right, id, id, pair, Inner).Take(2).Do())
+	})
+
+	t.Run("many matches", func(t *testing.T) {
+		t.Parallel()
+
+		l := make([]int, 10_000)
+		for i := range l {
+			l[i] = i
+		}
+		r := make([]int, 0, 3*len(l))
+		for k := 0; k < 3; k++ {
+			for i := 0; i < len(l); i += 2 {
+				r = append(r, i+k*len(l))
+			}
+		}
+		mod := func(x *int) int { return *x % len(l) }
+		res := HashJoin(Slice(l).Parallel(4), Slice(r).Parallel(4), id, mod, pair, Inner).Parallel(4).Do()
+		require.Equal(t, 3*len(l)/2, len(res))
+		for i, x := range res {
+			require.Equal(t, [2]int{i / 3 * 2, i/3*2 + i%3*len(l)}, x)
+		}
+	})
+
+	t.Run("endless left", func(t *testing.T) {
+		t.Parallel()
+
+		left := Func(func(i int) (int, bool) { return i % 10, true }).Parallel(4)
+		right := Slice([]int{7, 7, 9}).Parallel(2)
+		res := HashJoin(left, right, id, id, pair, Inner).Parallel(2).Take(5).Do()
+		// the probe of left is stopped once the terminal operation is over
+		require.Equal(t, [][2]int{{7, 7}, {7, 7}, {9, 9}, {7, 7}, {7, 7}}, res)
+	})
+
+	t.Run("struct keys", func(t *testing.T) {
+		t.Parallel()
+
+		type key struct {
+			name string
+			n    int
+			f    float64
+		}
+		lk := func(x *int) key { return key{name: strconv.Itoa(*x % 10), n: *x % 3, f: 0} }
+		negZero := math.Copysign(0, -1)
+		rk := func(x *int) key { return key{name: strconv.Itoa(*x), n: *x % 3, f: negZero} }
+		res := HashJoin(finite(30).Parallel(3), Slice([]int{1, 4}), lk, rk, pair, Semi).Parallel(3).Do()
+		require.Equal(t, [][2]int{{1, 1}, {4, 4}}, res)
+	})
+
+	t.Run("the left side stays lazy", func(t *testing.T) {
+		t.Parallel()
+
+		evaluated := 0
+		left := Func(func(i int) (int, bool) {
+			evaluated++
+			return i, true
+		}).Gen(10)
+		p := HashJoin(left, Slice([]int{2, 4}), id, id, pair, Semi)
+		require.Equal(t, 0, evaluated)
+		require.Equal(t, [][2]int{{2, 2}, {4, 4}}, p.Do())
+		require.Equal(t, 10, evaluated)
+	})
+}
@@ -0,0 +1,57 @@
+package internalpipe
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineError is an error of the line of the input, the lines are numbered from 1.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// JSONLines creates a pipe of the values of T decoded from the lines of r, the length is unknown.
+// The lines are read the way Scan does and decoded by the goroutines evaluating the pipe, blank lines are skipped.
+// A line failed to be decoded is skipped and the LineError is yeeted to the Yeti of the pipe.
+func JSONLines[T any](r io.Reader) Pipe[T] {
+	lines := Scan(r, bufio.ScanLines)
+	return Derive(lines, func(i int) (*T, bool) {
+		line, skipped := lines.Fn(i)
+		if skipped || strings.TrimSpace(*line) == "" {
+			return nil, true
+		}
+		var v T
+		if err := json.Unmarshal([]byte(*line), &v); err != nil {
+			lines.y.Yeet(&LineError{Line: i + 1, Err: err})
+			return nil, true
+		}
+		return &v, false
+	})
+}
+
+// WriteJSONLines writes the elements of the pipe to w as JSON values one per line in the order of the pipe.
+// The elements are encoded the way WriteTo formats them.
+// It stops at the first element failed to be encoded or written and returns the error,
+// the error of the context of the pipe once it's done.
+func WriteJSONLines[T any](w io.Writer, p Pipe[T]) error {
+	_, err := writeTo(w, p, func(x *T, b []byte) ([]byte, error) {
+		enc, err := json.Marshal(x)
+		if err != nil {
+			return b, err
+		}
+		return append(append(b, enc...), '\n'), nil
+	})
+	return err
+}
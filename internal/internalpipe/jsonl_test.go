@@ -0,0 +1,109 @@
+package internalpipe
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+}
+
+func events(n int) (string, []event) {
+	return fixture(n, func(i int) event {
+		return event{ID: i, Kind: "k" + strconv.Itoa(i%3)}
+	}, func(e event) string {
+		return `{"id":` + strconv.Itoa(e.ID) + `,"kind":"` + e.Kind + `"}`
+	})
+}
+
+func Test_JSONLines(t *testing.T) {
+	t.Parallel()
+
+	text, exp := events(10_000)
+	require.Equal(t, exp, JSONLines[event](strings.NewReader(text)).Parallel(8).Do())
+	require.Equal(t, exp[:10], JSONLines[event](strings.NewReader(text)).Take(10).Do())
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		in := "{\"id\":1}\n\nnot json\n  \n{\"id\":2}\n{\"id\":\"3\"}\n"
+		var handled []error
+		res := JSONLines[event](strings.NewReader(in)).
+			Yeti(NewYeti()).
+			Snag(func(err error) { handled = append(handled, err) }).
+			Parallel(4).
+			Do()
+		require.Equal(t, []event{{ID: 1}, {ID: 2}}, res)
+		require.Len(t, handled, 2)
+
+		// the lines are decoded in parallel, so the errors come in any order
+		lines := make([]int, 0, len(handled))
+		for _, err := range handled {
+			var le *LineError
+			require.True(t, errors.As(err, &le))
+			require.Contains(t, err.Error(), "line "+strconv.Itoa(le.Line)+": ")
+			lines = append(lines, le.Line)
+		}
+		require.ElementsMatch(t, []int{3, 6}, lines)
+	})
+}
+
+// failingWriter fails after n bytes are written.
+type failingWriter struct {
+	n   int
+	err error
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		return w.n, w.err
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func Test_WriteJSONLines(t *testing.T) {
+	t.Parallel()
+
+	text, exp := events(50_000)
+
+	for _, threads := range []uint16{1, 8} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSONLines(&buf, Slice(exp).Parallel(threads)))
+		require.Equal(t, text, buf.String())
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		p := Slice(exp).Filter(func(e *event) bool { return e.ID < 2 }).Parallel(4)
+		require.NoError(t, WriteJSONLines(&buf, p))
+		require.Equal(t, "{\"id\":0,\"kind\":\"k0\"}\n{\"id\":1,\"kind\":\"k1\"}\n", buf.String())
+	})
+
+	t.Run("no length", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSONLines(&buf, JSONLines[event](strings.NewReader(text)).Parallel(4).Take(50_000)))
+		require.Equal(t, text, buf.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		writeErr := errors.New("write error")
+		require.Equal(t, writeErr, WriteJSONLines(&failingWriter{n: 1 << 20, err: writeErr}, Slice(exp).Parallel(4)))
+
+		err := WriteJSONLines(&bytes.Buffer{}, Slice([]any{1, func() {}}))
+		require.Error(t, err)
+	})
+}
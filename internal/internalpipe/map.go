@@ -3,18 +3,59 @@ package internalpipe
 // Map applies given function to each element of the underlying slice
 // returns the slice where each element is n[i] = f(p[i]).
 func (p Pipe[T]) Map(fn func(T) T) Pipe[T] {
-	return Pipe[T]{
-		Fn: func(i int) (*T, bool) {
+	return MapTo(p, fn)
+}
+
+// MapTo applies fn to each element of the pipe changing the type of elements.
+// The result keeps all the settings of p.
+func MapTo[SrcT, DstT any](p Pipe[SrcT], fn func(SrcT) DstT) Pipe[DstT] {
+	var valFn func(int) DstT
+	if p.ValFn != nil {
+		valFn = func(i int) DstT {
+			return fn(p.ValFn(i))
+		}
+	}
+	var batchFn BatchFn[DstT]
+	if p.BatchFn != nil {
+		// the elements are mapped in place if the type is the same,
+		// a func is asserted instead of a slice since a slice in an interface is allocated
+		inPlace, same := any(func(s []DstT) []DstT { return s }).(func([]DstT) []SrcT)
+		batchFn = func(lf, rg int, out []DstT, skipped []bool) {
+			var in []SrcT
+			if same {
+				in = inPlace(out)
+			} else {
+				in = make([]SrcT, rg-lf)
+			}
+			p.BatchFn(lf, rg, in, skipped)
+			for j := range in[:rg-lf] {
+				if !skipped[j] {
+					out[j] = fn(in[j])
+				}
+			}
+		}
+	}
+
+	return Pipe[DstT]{
+		Fn: func(i int) (*DstT, bool) {
 			if obj, skipped := p.Fn(i); !skipped {
 				res := fn(*obj)
 				return &res, false
 			}
 			return nil, true
 		},
+		ValFn:         valFn,
+		BatchFn:       batchFn,
 		Len:           p.Len,
 		ValLim:        p.ValLim,
 		GoroutinesCnt: p.GoroutinesCnt,
 
-		y: p.y,
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+		stages:      p.stages,
 	}
 }
@@ -4,6 +4,20 @@ package internalpipe
 // if the second returning value of fn is false, the element is skipped (may be useful for error handling).
 // returns the slice where each element is n[i] = f(p[i]) if it is not skipped.
 func (p Pipe[T]) MapFilter(fn func(T) (T, bool)) Pipe[T] {
+	var batchFn BatchFn[T]
+	if p.BatchFn != nil {
+		batchFn = func(lf, rg int, out []T, skipped []bool) {
+			p.BatchFn(lf, rg, out, skipped)
+			var take bool
+			for j := range out[:rg-lf] {
+				if !skipped[j] {
+					out[j], take = fn(out[j])
+					skipped[j] = !take
+				}
+			}
+		}
+	}
+
 	return Pipe[T]{
 		Fn: func(i int) (*T, bool) {
 			if obj, skipped := p.Fn(i); !skipped {
@@ -12,10 +26,17 @@ func (p Pipe[T]) MapFilter(fn func(T) (T, bool)) Pipe[T] {
 			}
 			return nil, true
 		},
+		BatchFn:       batchFn,
 		Len:           p.Len,
 		ValLim:        p.ValLim,
 		GoroutinesCnt: p.GoroutinesCnt,
 
-		y: p.y,
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+		stages:      p.stages,
 	}
 }
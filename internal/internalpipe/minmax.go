@@ -0,0 +1,75 @@
+package internalpipe
+
+const notFound = -1
+
+type extremes[T any] struct {
+	min, max       *T
+	minIdx, maxIdx int
+}
+
+// MinMax returns the minimal and the maximal elements of the pipe or nils if the pipe is empty.
+// If there are several equal extreme elements, the one with the lowest index is returned.
+func (p Pipe[T]) MinMax(less func(*T, *T) bool) (*T, *T) {
+	e := p.extremes(less)
+	return e.min, e.max
+}
+
+// Min returns the minimal element of the pipe or nil if the pipe is empty.
+// If there are several minimal elements, the one with the lowest index is returned.
+func (p Pipe[T]) Min(less func(*T, *T) bool) *T {
+	return p.extremes(less).min
+}
+
+// Max returns the maximal element of the pipe or nil if the pipe is empty.
+// If there are several maximal elements, the one with the lowest index is returned.
+func (p Pipe[T]) Max(less func(*T, *T) bool) *T {
+	return p.extremes(less).max
+}
+
+// ArgMin returns the source index of the minimal element of the pipe or -1 if the pipe is empty.
+// If there are several minimal elements, the lowest index is returned.
+func (p Pipe[T]) ArgMin(less func(*T, *T) bool) int {
+	return p.extremes(less).minIdx
+}
+
+// ArgMax returns the source index of the maximal element of the pipe or -1 if the pipe is empty.
+// If there are several maximal elements, the lowest index is returned.
+func (p Pipe[T]) ArgMax(less func(*T, *T) bool) int {
+	return p.extremes(less).maxIdx
+}
+
+func (p Pipe[T]) extremes(less func(*T, *T) bool) extremes[T] {
+	return Fold(
+		p,
+		func() extremes[T] {
+			return extremes[T]{minIdx: notFound, maxIdx: notFound}
+		},
+		func(e extremes[T], i int, x *T) extremes[T] {
+			if e.min == nil {
+				return extremes[T]{min: x, max: x, minIdx: i, maxIdx: i}
+			}
+			if less(x, e.min) {
+				e.min, e.minIdx = x, i
+			}
+			if less(e.max, x) {
+				e.max, e.maxIdx = x, i
+			}
+			return e
+		},
+		func(a, b extremes[T]) extremes[T] {
+			switch {
+			case b.min == nil:
+				return a
+			case a.min == nil:
+				return b
+			}
+			if less(b.min, a.min) {
+				a.min, a.minIdx = b.min, b.minIdx
+			}
+			if less(a.max, b.max) {
+				a.max, a.maxIdx = b.max, b.maxIdx
+			}
+			return a
+		},
+	)
+}
@@ -0,0 +1,78 @@
+package internalpipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MinMax(t *testing.T) {
+	initA100k()
+	t.Parallel()
+
+	less := func(x, y *float64) bool { return *x < *y }
+
+	for _, cnt := range []uint16{1, 7} {
+		p := Slice(a100k).Parallel(cnt)
+
+		require.Equal(t, 0., *p.Min(less))
+		require.Equal(t, 99_999., *p.Max(less))
+		mn, mx := p.MinMax(less)
+		require.Equal(t, 0., *mn)
+		require.Equal(t, 99_999., *mx)
+		require.Equal(t, 0, p.ArgMin(less))
+		require.Equal(t, 99_999, p.ArgMax(less))
+	}
+
+	t.Run("ties are broken by the lowest index", func(t *testing.T) {
+		t.Parallel()
+
+		p := Func(func(i int) (int, bool) { return i % 10, true }).Gen(100_000).Parallel(7)
+		less := func(x, y *int) bool { return *x < *y }
+		require.Equal(t, 0, p.ArgMin(less))
+		require.Equal(t, 9, p.ArgMax(less))
+	})
+
+	t.Run("skipped elements", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a100k).Filter(func(x *float64) bool { return *x > 50_000 && *x < 60_000 }).Parallel(7)
+		require.Equal(t, 50_001., *p.Min(less))
+		require.Equal(t, 50_001, p.ArgMin(less))
+		require.Equal(t, 59_999, p.ArgMax(less))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice([]float64{}).Parallel(7)
+		mn, mx := p.MinMax(less)
+		require.Nil(t, mn)
+		require.Nil(t, mx)
+		require.Equal(t, -1, p.ArgMin(less))
+		require.Equal(t, -1, p.ArgMax(less))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var yeeted []error
+		y := NewYeti()
+		y.Snag(func(err error) { yeeted = append(yeeted, err) })
+		p := Slice(a100k).Parallel(7).WithContext(ctx).Yeti(y)
+		mn, mx := p.MinMax(less)
+		require.Nil(t, mn)
+		require.Nil(t, mx)
+		require.Nil(t, p.Min(less))
+		require.Nil(t, p.Max(less))
+		require.Equal(t, -1, p.ArgMin(less))
+		require.Equal(t, -1, p.ArgMax(less))
+		require.NotEmpty(t, yeeted)
+		for _, err := range yeeted {
+			require.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package internalpipe
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxPagesAhead is the maximal amount of pages Paged fetches ahead of the page being read.
+const maxPagesAhead = 8
+
+// Paged creates a pipe of the elements of the pages fetched by fetch starting with the page first,
+// the length is unknown. The pages are flattened in order and end at the first empty page.
+// The following pages are fetched concurrently ahead of the page being read: one page at first,
+// twice as many with each next page, up to maxPagesAhead of them, see pagePrefetch.
+// fetch gets the context of the pipe canceled once no more pages are needed.
+// The error of fetching a page ends the pipe and is yeeted to the Yeti of the pipe.
+func Paged[T any](first int, fetch func(ctx context.Context, page int) ([]T, error)) Pipe[T] {
+	y := newRelay()
+	f := &pagePrefetch[T]{fetch: fetch, next: first}
+	var items []T
+	return sequenceWith(y, func() (T, bool) {
+		var zero T
+		for len(items) == 0 {
+			page, res := f.take()
+			if res.err != nil {
+				y.Yeet(fmt.Errorf("page %d: %w", page, res.err))
+				return zero, false
+			}
+			if len(res.items) == 0 {
+				return zero, false
+			}
+			items = res.items
+		}
+		val := items[0]
+		items = items[1:]
+		return val, true
+	}, seqHooks{start: f.prepare, idle: f.idle, release: f.stop})
+}
+
+// PagedCursor creates a pipe of the elements of the pages fetched by fetch, the length is unknown.
+// fetch gets the cursor of the page and returns its elements and the cursor of the next page,
+// the first page is fetched with the empty cursor. The pages are fetched one by one as the elements run out
+// and flattened in order, the pipe ends at an empty page or after the page with the empty next cursor.
+// fetch gets the context of the pipe or context.Background() if it's not set.
+// The error of fetching a page ends the pipe and is yeeted to the Yeti of the pipe.
+func PagedCursor[T any](fetch func(ctx context.Context, cursor string) ([]T, string, error)) Pipe[T] {
+	y := newRelay()
+	var (
+		ctx    = context.Background()
+		items  []T
+		cursor string
+		last   bool
+	)
+	return sequenceWith(y, func() (T, bool) {
+		var zero T
+		for len(items) == 0 {
+			if last {
+				return zero, false
+			}
+			page, next, err := fetch(ctx, cursor)
+			if err != nil {
+				y.Yeet(fmt.Errorf("cursor %q: %w", cursor, err))
+				return zero, false
+			}
+			if len(page) == 0 {
+				return zero, false
+			}
+			items, cursor, last = page, next, next == ""
+		}
+		val := items[0]
+		items = items[1:]
+		return val, true
+	}, seqHooks{start: func(e evaluation) {
+		if e.ctx != nil {
+			ctx = e.ctx
+		}
+	}})
+}
+
+// pageFetch is the fetching of a page, it's done by a goroutine of the executor if one starts it.
+type pageFetch[T any] struct {
+	page  int
+	items []T
+	err   error
+	done  bool
+	wait  func()
+}
+
+// pagePrefetch fetches the pages in order keeping a growing window of the following pages
+// being fetched on the executor of the pipe. A page is fetched by the goroutine reading it
+// if no goroutine of the executor has started it by then. The fetches get a context canceled
+// once the source is idle or the terminal operation is over.
+type pagePrefetch[T any] struct {
+	fetch   func(context.Context, int) ([]T, error)
+	next    int
+	ahead   int
+	pending []*pageFetch[T]
+
+	ex     *Executor
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// prepare makes the context of the fetches out of the context of the pipe and takes the executor of the pipe.
+func (f *pagePrefetch[T]) prepare(e evaluation) {
+	parent := e.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	f.ctx, f.cancel = context.WithCancel(parent)
+	f.ex = e.ex
+}
+
+// take waits for the next page and returns its number along with the result.
+func (f *pagePrefetch[T]) take() (int, fetchedPage[T]) {
+	f.ahead = min(max(f.ahead*2, 1), maxPagesAhead)
+	for len(f.pending) < f.ahead {
+		pf := &pageFetch[T]{page: f.next + len(f.pending), wait: noop}
+		if f.ex != nil {
+			pf.wait = f.ex.start(1, func(int) {
+				pf.items, pf.err = f.fetch(f.ctx, pf.page)
+				pf.done = true
+			})
+		}
+		f.pending = append(f.pending, pf)
+	}
+
+	pf := f.pending[0]
+	f.pending = f.pending[1:]
+	f.next++
+	pf.wait()
+	if !pf.done {
+		pf.items, pf.err = f.fetch(f.ctx, pf.page)
+	}
+	return pf.page, fetchedPage[T]{items: pf.items, err: pf.err}
+}
+
+// idle cancels the fetches ahead once no more pages are needed.
+func (f *pagePrefetch[T]) idle() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// stop cancels the fetches ahead, drops the ones not started yet and waits for the started ones.
+func (f *pagePrefetch[T]) stop() {
+	f.idle()
+	for _, pf := range f.pending {
+		pf.wait()
+	}
+	f.pending = nil
+}
+
+// fetchedPage is the result of fetching a page.
+type fetchedPage[T any] struct {
+	items []T
+	err   error
+}
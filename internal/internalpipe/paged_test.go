@@ -0,0 +1,196 @@
+package internalpipe
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pages splits a into the pages of size elements.
+func pages(a []int, size int) [][]int {
+	var res [][]int
+	for lf := 0; lf < len(a); lf += size {
+		res = append(res, a[lf:min(lf+size, len(a))])
+	}
+	return res
+}
+
+func Test_Paged(t *testing.T) {
+	t.Parallel()
+
+	_, exp := fixture(10_000, itself[int], strconv.Itoa)
+	ps := pages(exp, 100)
+	fetch := func(_ context.Context, page int) ([]int, error) {
+		if page-1 < len(ps) {
+			return ps[page-1], nil
+		}
+		return nil, nil
+	}
+
+	require.Equal(t, exp, Paged(1, fetch).Take(math.MaxInt).Do())
+	require.Equal(t, exp, Paged(1, fetch).Parallel(8).Take(math.MaxInt).Do())
+	require.Equal(t, exp[:250], Paged(1, fetch).Parallel(4).Take(250).Do())
+	require.Equal(t, exp[1000:], Paged(11, fetch).Parallel(4).Take(math.MaxInt).Do())
+	require.Empty(t, Paged(1, func(context.Context, int) ([]int, error) { return nil, nil }).Take(math.MaxInt).Do())
+
+	t.Run("stops at an empty page", func(t *testing.T) {
+		res := Paged(0, func(_ context.Context, page int) ([]int, error) {
+			if page == 3 {
+				return nil, nil
+			}
+			return []int{page}, nil
+		}).Take(math.MaxInt).Do()
+		require.Equal(t, []int{0, 1, 2}, res)
+	})
+	t.Run("error", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		y := NewYeti()
+		var errs []error
+		y.Snag(func(err error) { errs = append(errs, err) })
+
+		res := Paged(0, func(_ context.Context, page int) ([]int, error) {
+			if page >= 5 {
+				return nil, errFetch
+			}
+			return ps[page], nil
+		}).Parallel(4).Yeti(y).Take(math.MaxInt).Do()
+		require.Equal(t, exp[:500], res)
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], errFetch)
+		require.EqualError(t, errs[0], "page 5: fetch failed")
+	})
+	t.Run("prefetch is canceled", func(t *testing.T) {
+		ex := NewExecutor(2 * maxPagesAhead)
+		defer ex.Close()
+		var running atomic.Int64
+		ahead := make(chan struct{})
+		canceled := make(chan struct{}, maxPagesAhead)
+		res := Paged(0, func(ctx context.Context, page int) ([]int, error) {
+			running.Add(1)
+			defer running.Add(-1)
+			switch {
+			case page == 2:
+				// the last page needed is fetched along with the next one
+				<-ahead
+			case page == 3:
+				close(ahead)
+			}
+			if page < 3 {
+				return ps[page], nil
+			}
+			// the pages past the ones needed are fetched until the context is canceled
+			<-ctx.Done()
+			canceled <- struct{}{}
+			return nil, ctx.Err()
+		}).Parallel(4).WithExecutor(ex).Take(250).Do()
+		require.Equal(t, exp[:250], res)
+		// the started fetches are over once the terminal operation is
+		require.Zero(t, running.Load())
+		require.NotEmpty(t, canceled)
+	})
+	t.Run("executor", func(t *testing.T) {
+		// the pages not fetched ahead are fetched by the goroutine reading them
+		ex := NewExecutor(0)
+		require.Equal(t, exp, Paged(1, fetch).Parallel(4).WithExecutor(ex).Take(math.MaxInt).Do())
+	})
+}
+
+func Test_pagePrefetch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mx      sync.Mutex
+		fetched []int
+		running atomic.Int64
+		maxRun  atomic.Int64
+	)
+	ex := NewExecutor(maxPagesAhead)
+	defer ex.Close()
+	f := &pagePrefetch[int]{next: 0, fetch: func(_ context.Context, page int) ([]int, error) {
+		if r := running.Add(1); r > maxRun.Load() {
+			maxRun.Store(r)
+		}
+		defer running.Add(-1)
+		time.Sleep(time.Millisecond)
+
+		mx.Lock()
+		fetched = append(fetched, page)
+		mx.Unlock()
+		return []int{page}, nil
+	}}
+
+	f.prepare(evaluation{ex: ex})
+	defer f.stop()
+	page, res := f.take()
+	require.Equal(t, 0, page)
+	require.Equal(t, []int{0}, res.items)
+	mx.Lock()
+	require.Equal(t, []int{0}, fetched, "the first page is fetched alone")
+	mx.Unlock()
+
+	for i := 1; i < 50; i++ {
+		page, res := f.take()
+		require.Equal(t, i, page)
+		require.Equal(t, []int{i}, res.items, strconv.Itoa(i))
+	}
+	require.LessOrEqual(t, len(f.pending), maxPagesAhead)
+	require.Greater(t, maxRun.Load(), int64(1), "the pages are fetched concurrently")
+}
+
+func Test_PagedCursor(t *testing.T) {
+	t.Parallel()
+
+	_, exp := fixture(10_000, itself[int], strconv.Itoa)
+	ps := pages(exp, 100)
+	fetch := func(_ context.Context, cursor string) ([]int, string, error) {
+		page := 0
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+		next := ""
+		if page+1 < len(ps) {
+			next = strconv.Itoa(page + 1)
+		}
+		return ps[page], next, nil
+	}
+
+	require.Equal(t, exp, PagedCursor(fetch).Take(math.MaxInt).Do())
+	require.Equal(t, exp, PagedCursor(fetch).Parallel(8).Take(math.MaxInt).Do())
+	require.Equal(t, exp[:250], PagedCursor(fetch).Parallel(4).Take(250).Do())
+
+	t.Run("stops at an empty page", func(t *testing.T) {
+		calls := 0
+		res := PagedCursor(func(_ context.Context, cursor string) ([]int, string, error) {
+			calls++
+			if cursor == "b" {
+				return nil, "c", nil
+			}
+			return []int{1, 2}, "b", nil
+		}).Take(math.MaxInt).Do()
+		require.Equal(t, []int{1, 2}, res)
+		require.Equal(t, 2, calls)
+	})
+	t.Run("error", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		y := NewYeti()
+		var errs []error
+		y.Snag(func(err error) { errs = append(errs, err) })
+
+		res := PagedCursor(func(ctx context.Context, cursor string) ([]int, string, error) {
+			if cursor == "3" {
+				return nil, "", errFetch
+			}
+			return fetch(ctx, cursor)
+		}).Parallel(4).Yeti(y).Take(math.MaxInt).Do()
+		require.Equal(t, exp[:300], res)
+		require.Len(t, errs, 1)
+		require.EqualError(t, errs[0], `cursor "3": fetch failed`)
+	})
+}
@@ -1,36 +1,94 @@
 package internalpipe
 
 import (
+	"context"
 	"math"
 
 	"golang.org/x/exp/constraints"
 )
 
-const (
-	panicLimitExceededMsg = "the limit have been exceeded, but the result is not calculated"
-)
+// unboundedLimit is the evaluation limit of a pipe with neither length nor limit set.
+const unboundedLimit = math.MaxInt - 1
 
 type GeneratorFn[T any] func(int) (*T, bool)
 
+// BatchFn evaluates the elements [lf, rg) into out[:rg-lf] setting skipped[j] for each skipped element lf+j.
+// It is called only for rg <= Len.
+type BatchFn[T any] func(lf, rg int, out []T, skipped []bool)
+
 type Pipe[T any] struct {
-	Fn            GeneratorFn[T]
+	Fn GeneratorFn[T]
+	// ValFn is set if the pipe never skips an element in [0, Len), it returns the element by value.
+	ValFn func(int) T
+	// BatchFn is set if each stage of the pipe is able to evaluate a block of elements at once.
+	BatchFn       BatchFn[T]
 	Len           int
 	ValLim        int
 	GoroutinesCnt int
 
-	y yeti
+	y      yeti
+	ex     *Executor
+	ctx    context.Context
+	src    source
+	stages []stage
+	// atParallel is set by Parallel and reset by any stage added after it
+	atParallel bool
+	// parallelSet is set by the first Parallel, even if it sets the default amount of goroutines
+	parallelSet bool
+	auto        bool
 }
 
 // Parallel set n - the amount of goroutines to run on.
-// Only the first Parallel() in a pipe chain is applied.
+// The first Parallel() in a pipe chain is applied to all the stages before and after it.
+// Parallel() called after some more stages are added makes a stage boundary: the stages before it keep
+// their amount of goroutines and pass the elements to the following stages through a bounded buffer.
+// Parallel() called right after another Parallel() is not applied.
 func (p Pipe[T]) Parallel(n uint16) Pipe[T] {
-	if p.GoroutinesCnt != defaultParallelWrks || n < 1 {
+	switch {
+	case n < 1 || p.atParallel:
+		return p
+	case !p.parallelSet && len(p.stages) == 0:
+		p.GoroutinesCnt = int(n)
+		p.atParallel, p.parallelSet = true, true
 		return p
+	default:
+		return withBoundary(p, int(n))
 	}
-	p.GoroutinesCnt = int(n)
+}
+
+// WithExecutor sets the executor to evaluate the pipe on instead of the default one.
+func (p Pipe[T]) WithExecutor(ex *Executor) Pipe[T] {
+	p.ex = ex
 	return p
 }
 
+// WithContext sets the context of the pipe evaluation.
+// Once ctx is done, the terminal operations stop evaluating the pipe and yeet the error of ctx
+// to the Yeti of the pipe, their result is incomplete then.
+func (p Pipe[T]) WithContext(ctx context.Context) Pipe[T] {
+	p.ctx = ctx
+	return p
+}
+
+// Derive creates a pipe of DstT generated by fn with all the settings of p.
+// It is used to build the stages changing the type of a pipe.
+func Derive[SrcT, DstT any](p Pipe[SrcT], fn GeneratorFn[DstT]) Pipe[DstT] {
+	return Pipe[DstT]{
+		Fn:            fn,
+		Len:           p.Len,
+		ValLim:        p.ValLim,
+		GoroutinesCnt: p.GoroutinesCnt,
+
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+		stages:      p.stages,
+	}
+}
+
 // Take is used to set the amount of values expected to be in result slice.
 // It's applied only the first Gen() or Take() function in the pipe.
 func (p Pipe[T]) Take(n int) Pipe[T] {
@@ -52,8 +110,10 @@ func (p Pipe[T]) Gen(n int) Pipe[T] {
 }
 
 // Count evaluates all the pipeline and returns the amount of items.
+// An endless pipe with a limit has exactly the limit of them, unless its source ends,
+// its stages skip the elements or its context is done, then they are counted through the stream.
 func (p Pipe[T]) Count() int {
-	if p.limitSet() {
+	if p.limitSet() && p.src == nil && len(p.stages) == 0 && p.ctx == nil {
 		return p.ValLim
 	}
 	_, cnt := p.do(false)
@@ -68,7 +128,33 @@ func (p *Pipe[T]) limit() int {
 	case p.limitSet():
 		return p.ValLim
 	default:
-		return math.MaxInt - 1
+		return unboundedLimit
+	}
+}
+
+// Executor returns the executor the pipe is evaluated on.
+func (p Pipe[T]) Executor() *Executor {
+	if p.ex != nil {
+		return p.ex
+	}
+	return DefaultExecutor()
+}
+
+// canceled reports if the context of the pipe is done.
+func (p *Pipe[T]) canceled() bool {
+	return isDone(p.ctx)
+}
+
+// isDone reports if ctx is set and done.
+func isDone(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
 	}
 }
 
@@ -94,15 +180,16 @@ func max[T constraints.Ordered](a, b T) T {
 	return a
 }
 
-func divUp(a, b int) int {
-	return int(math.Ceil(float64(a) / float64(b)))
+// Min returns the lesser of a and b.
+func Min[T constraints.Ordered](a, b T) T {
+	return min(a, b)
 }
 
-func genTickets(n int) chan struct{} {
-	tickets := make(chan struct{}, n)
-	n = max(n, 1)
-	for i := 0; i < n; i++ {
-		tickets <- struct{}{}
-	}
-	return tickets
+// Max returns the greater of a and b.
+func Max[T constraints.Ordered](a, b T) T {
+	return max(a, b)
+}
+
+func divUp(a, b int) int {
+	return int(math.Ceil(float64(a) / float64(b)))
 }
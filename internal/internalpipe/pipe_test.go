@@ -1,7 +1,9 @@
 package internalpipe
 
 import (
+	"bufio"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -36,11 +38,6 @@ func Test_divUp(t *testing.T) {
 	require.Equal(t, divUp(1, 1345), 1)
 }
 
-func Test_genTickets(t *testing.T) {
-	t.Parallel()
-	require.Equal(t, len(genTickets(100)), 100)
-}
-
 func Test_do(t *testing.T) {
 	t.Parallel()
 
@@ -198,6 +195,14 @@ func Test_Count(t *testing.T) {
 		GoroutinesCnt: 1,
 	}
 	require.Equal(t, 10000, p.Count())
+
+	// the source ends before the limit
+	lines := func() Pipe[string] { return Scan(strings.NewReader("a\nbb\nc\nbb\n"), bufio.ScanLines) }
+	require.Equal(t, 4, lines().Take(math.MaxInt).Count())
+	require.Equal(t, 4, lines().Parallel(3).Take(math.MaxInt).Count())
+	isBB := func(s *string) bool { return *s == "bb" }
+	require.Equal(t, 2, lines().Filter(isBB).Take(10).Count())
+	require.Equal(t, 2, lines().Parallel(3).Filter(isBB).Take(10).Count())
 }
 
 func Test_limit(t *testing.T) {
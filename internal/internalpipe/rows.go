@@ -0,0 +1,46 @@
+package internalpipe
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Rows creates a pipe of the values scanned from rows by scan, the length is unknown.
+// The rows are scanned one at a time in order the way database/sql requires, each row is scanned once
+// by the goroutine reaching it first. Only the rows of the chunks being evaluated are kept,
+// at most seqWindow of them, so the following stages run in parallel with a bounded look-ahead.
+// rows are closed once the terminal operation is over, even if it stops before the last row.
+// A row failed to be scanned is skipped and its error is yeeted to the Yeti of the pipe along with
+// the errors of iterating and closing rows.
+func Rows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) Pipe[T] {
+	y := newRelay()
+	var once sync.Once
+	closeRows := func() {
+		once.Do(func() {
+			if err := rows.Err(); err != nil {
+				y.Yeet(err)
+			}
+			if err := rows.Close(); err != nil {
+				y.Yeet(err)
+			}
+		})
+	}
+
+	n := 0
+	return sequence(y, func() (T, bool) {
+		for rows.Next() {
+			n++
+			val, err := scan(rows)
+			if err != nil {
+				y.Yeet(fmt.Errorf("row %d: %w", n, err))
+				continue
+			}
+			return val, true
+		}
+
+		closeRows()
+		var zero T
+		return zero, false
+	}, closeRows)
+}
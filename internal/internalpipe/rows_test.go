@@ -0,0 +1,176 @@
+package internalpipe
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errFakeRows = errors.New("fake rows failed")
+
+// fakeDriver serves the queries of the form "n[,fail]" with n rows of (id, name), the rows fail at the fail row.
+type fakeDriver struct{}
+
+func init() {
+	sql.Register("internalpipe_fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	parts := strings.Split(query, ",")
+	n, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	fail := -1
+	if len(parts) > 1 {
+		if fail, err = strconv.Atoi(parts[1]); err != nil {
+			return nil, err
+		}
+	}
+	return &fakeRows{n: n, fail: fail}, nil
+}
+
+type fakeRows struct {
+	i, n, fail int
+}
+
+func (*fakeRows) Columns() []string { return []string{"id", "name"} }
+
+func (*fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	switch {
+	case r.i == r.fail:
+		return errFakeRows
+	case r.i == r.n:
+		return io.EOF
+	}
+	dest[0], dest[1] = int64(r.i), "name"+strconv.Itoa(r.i)
+	r.i++
+	return nil
+}
+
+type fakeRow struct {
+	ID   int
+	Name string
+}
+
+func scanFakeRow(rows *sql.Rows) (fakeRow, error) {
+	var r fakeRow
+	err := rows.Scan(&r.ID, &r.Name)
+	return r, err
+}
+
+func fakeQuery(t *testing.T, query string) *sql.Rows {
+	db, err := sql.Open("internalpipe_fake", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query(query)
+	require.NoError(t, err)
+	return rows
+}
+
+func Test_Rows(t *testing.T) {
+	t.Parallel()
+
+	exp := make([]fakeRow, 10_000)
+	for i := range exp {
+		exp[i] = fakeRow{ID: i, Name: "name" + strconv.Itoa(i)}
+	}
+
+	t.Run("single thread", func(t *testing.T) {
+		require.Equal(t, exp, Rows(fakeQuery(t, "10000"), scanFakeRow).Take(math.MaxInt).Do())
+	})
+	t.Run("threads", func(t *testing.T) {
+		res := Rows(fakeQuery(t, "10000"), scanFakeRow).Parallel(8).Take(math.MaxInt).Do()
+		require.Equal(t, exp, res)
+	})
+	t.Run("map and filter", func(t *testing.T) {
+		res := Rows(fakeQuery(t, "10000"), scanFakeRow).Parallel(4).
+			Filter(func(r *fakeRow) bool { return r.ID%10 == 0 }).
+			Map(func(r fakeRow) fakeRow {
+				r.Name = strings.ToUpper(r.Name)
+				return r
+			}).
+			Take(math.MaxInt).
+			Do()
+		require.Len(t, res, 1000)
+		require.Equal(t, fakeRow{ID: 9990, Name: "NAME9990"}, res[999])
+	})
+	t.Run("take", func(t *testing.T) {
+		rows := fakeQuery(t, "10000")
+		require.Equal(t, exp[:100], Rows(rows, scanFakeRow).Parallel(4).Take(100).Do())
+		// the rows are closed though they are not read to the end
+		_, err := rows.Columns()
+		require.Error(t, err)
+	})
+	t.Run("first", func(t *testing.T) {
+		rows := fakeQuery(t, "10000")
+		p := Rows(rows, scanFakeRow).Parallel(4).Filter(func(r *fakeRow) bool { return r.ID > 5000 })
+		require.Equal(t, exp[5001], *p.First())
+		_, err := rows.Columns()
+		require.Error(t, err)
+	})
+}
+
+func Test_Rows_errors(t *testing.T) {
+	t.Parallel()
+
+	snag := func() (*Yeti, *[]error) {
+		y := NewYeti()
+		errs := new([]error)
+		y.Snag(func(err error) { *errs = append(*errs, err) })
+		return y, errs
+	}
+
+	t.Run("iteration", func(t *testing.T) {
+		y, errs := snag()
+		res := Rows(fakeQuery(t, "100,40"), scanFakeRow).Parallel(4).Yeti(y).Take(math.MaxInt).Do()
+		require.Len(t, res, 40)
+		require.Len(t, *errs, 1)
+		require.ErrorIs(t, (*errs)[0], errFakeRows)
+	})
+	t.Run("scan", func(t *testing.T) {
+		y, errs := snag()
+		res := Rows(fakeQuery(t, "100"), func(rows *sql.Rows) (fakeRow, error) {
+			r, err := scanFakeRow(rows)
+			if err == nil && r.ID%25 == 0 {
+				err = errFakeRows
+			}
+			return r, err
+		}).Parallel(4).Yeti(y).Take(math.MaxInt).Do()
+		require.Len(t, res, 96)
+
+		msgs := make([]string, len(*errs))
+		for i, err := range *errs {
+			require.ErrorIs(t, err, errFakeRows)
+			msgs[i] = err.Error()
+		}
+		sort.Strings(msgs)
+		require.Equal(t, []string{
+			"row 1: fake rows failed",
+			"row 26: fake rows failed",
+			"row 51: fake rows failed",
+			"row 76: fake rows failed",
+		}, msgs)
+	})
+}
@@ -0,0 +1,182 @@
+package internalpipe
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// seqItem is an element read by a seqSource and not yet taken by the pipe.
+type seqItem[E any] struct {
+	val   E
+	taken bool
+}
+
+// seqWindow is the maximal amount of elements a seqSource reads ahead of the lowest not taken one.
+const seqWindow = 1 << 12
+
+// seqSource gives out the elements of a sequential reader as they are requested by index.
+// It keeps only the elements read but not yet taken, at most seqWindow of them: the requests past the window
+// wait for the front elements to be taken or dropped. Each element is given out once, so the source is read
+// by a single terminal operation: the following ones get no elements and yeet ErrSourceReused.
+type seqSource[E any] struct {
+	y      *relay
+	opened atomic.Bool
+	hooks  seqHooks
+
+	mx   sync.Mutex
+	cond *sync.Cond
+	// read reads the next element, it returns false at the end
+	read    func() (E, bool)
+	buf     []seqItem[E]
+	floor   int
+	from    int
+	waiting int
+
+	done atomic.Bool
+	end  int
+}
+
+// Scan creates a pipe of the tokens of r split by split, the length is unknown.
+// The tokens are read incrementally as the pipe is evaluated, so r is read once by a single terminal operation.
+// The read error is yeeted to the Yeti of the pipe, the tokens read before it stay in the pipe.
+func Scan(r io.Reader, split bufio.SplitFunc) Pipe[string] {
+	sc := bufio.NewScanner(r)
+	sc.Split(split)
+	y := newRelay()
+	return sequence(y, func() (string, bool) {
+		if sc.Scan() {
+			return sc.Text(), true
+		}
+		if err := sc.Err(); err != nil {
+			y.Yeet(err)
+		}
+		return "", false
+	}, nil)
+}
+
+// seqHooks are the calls a seqSource makes to its reader besides reading it, each of them may be nil.
+type seqHooks struct {
+	// start prepares the reader for the first terminal operation before anything is read,
+	// so the reader can use its executor, workers and context
+	start func(evaluation)
+	// idle tells the reader no more elements are read by the terminal operation, it may be called more than once
+	idle func()
+	// release frees the reader once the first terminal operation is over
+	release func()
+}
+
+// sequence creates a pipe of the elements read by read one by one yeeting the errors to y, the length is unknown.
+// release is called once the first terminal operation reading the pipe is over, it may be nil.
+func sequence[E any](y *relay, read func() (E, bool), release func()) Pipe[E] {
+	return sequenceWith(y, read, seqHooks{release: release})
+}
+
+// sequenceWith creates a pipe the way sequence does with the hooks of the reader.
+func sequenceWith[E any](y *relay, read func() (E, bool), hooks seqHooks) Pipe[E] {
+	s := newSeqSource(y, read, hooks)
+	return Pipe[E]{
+		Fn:            s.get,
+		Len:           notSet,
+		ValLim:        notSet,
+		GoroutinesCnt: defaultParallelWrks,
+
+		y:   y,
+		src: s,
+	}
+}
+
+// newSeqSource creates a source of the elements read by read one by one.
+func newSeqSource[E any](y *relay, read func() (E, bool), hooks seqHooks) *seqSource[E] {
+	s := &seqSource[E]{y: y, read: read, hooks: hooks, from: unboundedLimit}
+	s.cond = sync.NewCond(&s.mx)
+	return s
+}
+
+// open binds the relay of the source to e.y, the source is ended for all the terminal operations but the first one.
+// The reader is started for the first one and released once it's over even if it's not read to the end.
+func (s *seqSource[E]) open(e evaluation) func() {
+	unbind := s.y.bind(e.y)
+	if s.opened.Swap(true) {
+		s.mx.Lock()
+		s.buf, s.end = nil, 0
+		s.done.Store(true)
+		s.mx.Unlock()
+		s.y.Yeet(ErrSourceReused)
+		return unbind
+	}
+	if s.hooks.start != nil {
+		s.hooks.start(e)
+	}
+	return func() {
+		if s.hooks.release != nil {
+			s.hooks.release()
+		}
+		unbind()
+	}
+}
+
+func (s *seqSource[E]) ended(i int) bool {
+	return s.done.Load() && i >= s.end
+}
+
+func (s *seqSource[E]) get(i int) (*E, bool) {
+	if s.done.Load() && i >= s.end {
+		return nil, true
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for !s.done.Load() && i < s.from && i >= s.floor+len(s.buf) {
+		if len(s.buf) < seqWindow {
+			s.next()
+			continue
+		}
+		s.waiting++
+		s.cond.Wait()
+		s.waiting--
+	}
+	if i >= s.from || i < s.floor || i >= s.floor+len(s.buf) || s.buf[i-s.floor].taken {
+		return nil, true
+	}
+
+	item := &s.buf[i-s.floor]
+	val := item.val
+	var zero E
+	item.val, item.taken = zero, true
+	floor := s.floor
+	for len(s.buf) != 0 && s.buf[0].taken {
+		s.buf = s.buf[1:]
+		s.floor++
+	}
+	if s.waiting != 0 && s.floor != floor {
+		s.cond.Broadcast()
+	}
+	return &val, false
+}
+
+// drop ends the requests of the elements from the index i on, they wait no more for the window to move.
+// The reader is told it's idle once all the elements requested are read.
+func (s *seqSource[E]) drop(i int) {
+	s.mx.Lock()
+	s.from = min(s.from, i)
+	idle := s.from <= s.floor+len(s.buf)
+	s.mx.Unlock()
+	s.cond.Broadcast()
+	if idle && s.hooks.idle != nil {
+		s.hooks.idle()
+	}
+}
+
+// next reads the next element, s.mx must be locked.
+func (s *seqSource[E]) next() {
+	if val, ok := s.read(); ok {
+		s.buf = append(s.buf, seqItem[E]{val: val})
+		return
+	}
+	s.end = s.floor + len(s.buf)
+	s.done.Store(true)
+	s.cond.Broadcast()
+}
@@ -0,0 +1,168 @@
+package internalpipe
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lines(n int) (string, []string) {
+	return fixture(n, func(i int) string { return "line " + strconv.Itoa(i) }, itself[string])
+}
+
+// failingReader returns err after the data is read.
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f failingReader) Read(b []byte) (int, error) {
+	n, err := f.r.Read(b)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+func Test_Scan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single thread", func(t *testing.T) {
+		t.Parallel()
+
+		text, exp := lines(10_000)
+		res := Scan(strings.NewReader(text), bufio.ScanLines).Do()
+		require.Equal(t, exp, res)
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+
+		text, exp := lines(100_000)
+		res := Scan(strings.NewReader(text), bufio.ScanLines).
+			Map(func(s string) string { return strings.ToUpper(s) }).
+			Parallel(8).
+			Do()
+		require.Len(t, res, len(exp))
+		for i := range exp {
+			require.Equal(t, strings.ToUpper(exp[i]), res[i])
+		}
+	})
+
+	t.Run("take", func(t *testing.T) {
+		t.Parallel()
+
+		text, exp := lines(100_000)
+		r := strings.NewReader(text)
+		res := Scan(r, bufio.ScanLines).Take(1000).Parallel(4).Do()
+		require.Equal(t, exp[:1000], res)
+		// the rest of the lines is not read
+		require.Greater(t, r.Len(), len(text)/2)
+	})
+
+	t.Run("words", func(t *testing.T) {
+		t.Parallel()
+
+		res := Scan(strings.NewReader("a bb  ccc\n d"), bufio.ScanWords).Do()
+		require.Equal(t, []string{"a", "bb", "ccc", "d"}, res)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, []string{}, Scan(strings.NewReader(""), bufio.ScanLines).Parallel(4).Do())
+		require.Nil(t, Scan(strings.NewReader(""), bufio.ScanLines).First())
+	})
+}
+
+func Test_Scan_error(t *testing.T) {
+	t.Parallel()
+
+	readErr := errors.New("read error")
+	text, exp := lines(1000)
+
+	t.Run("yeti", func(t *testing.T) {
+		t.Parallel()
+
+		var handled []error
+		y := NewYeti()
+		res := Scan(failingReader{strings.NewReader(text), readErr}, bufio.ScanLines).
+			Filter(func(*string) bool { return true }).
+			Yeti(y).
+			Snag(func(err error) { handled = append(handled, err) }).
+			Parallel(4).
+			Do()
+		require.Equal(t, exp, res)
+		require.Equal(t, []error{readErr}, handled)
+	})
+
+	t.Run("snag", func(t *testing.T) {
+		t.Parallel()
+
+		var handled []error
+		Scan(failingReader{strings.NewReader(text), readErr}, bufio.ScanLines).
+			Snag(func(err error) { handled = append(handled, err) }).
+			Do()
+		require.Equal(t, []error{readErr}, handled)
+	})
+
+	t.Run("yetis of copies", func(t *testing.T) {
+		t.Parallel()
+
+		snag := func() (*Yeti, *[]error) {
+			y := NewYeti()
+			errs := new([]error)
+			y.Snag(func(err error) { *errs = append(*errs, err) })
+			return y, errs
+		}
+		y1, errs1 := snag()
+		y2, errs2 := snag()
+		p := Scan(failingReader{strings.NewReader(text), readErr}, bufio.ScanLines)
+		first, second := p.Yeti(y1), p.Yeti(y2)
+
+		require.Equal(t, exp, first.Do())
+		require.Equal(t, []error{readErr}, *errs1)
+		require.Empty(t, *errs2)
+
+		require.Empty(t, second.Do())
+		require.Equal(t, []error{ErrSourceReused}, *errs2)
+		require.Equal(t, []error{readErr}, *errs1)
+	})
+}
+
+func Test_sequence_window(t *testing.T) {
+	t.Parallel()
+
+	var read atomic.Int64
+	p := sequence(newRelay(), func() (int, bool) {
+		return int(read.Add(1)) - 1, true
+	}, nil)
+
+	far := make(chan bool)
+	go func() {
+		_, skipped := p.Fn(seqWindow + 10)
+		far <- skipped
+	}()
+	require.Eventually(t, func() bool { return read.Load() == seqWindow }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	// the request past the window waits for the front elements to be taken
+	require.Equal(t, int64(seqWindow), read.Load())
+
+	x, skipped := p.Fn(0)
+	require.False(t, skipped)
+	require.Equal(t, 0, *x)
+	require.Eventually(t, func() bool { return read.Load() == seqWindow+1 }, time.Second, time.Millisecond)
+
+	p.src.drop(seqWindow)
+	require.True(t, <-far)
+	x, skipped = p.Fn(seqWindow - 1)
+	require.False(t, skipped)
+	require.Equal(t, seqWindow-1, *x)
+}
@@ -0,0 +1,206 @@
+package internalpipe
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// chunkDiv sets the part of the remaining own range a worker takes at once.
+	chunkDiv = 4
+	// minUnboundedChunk is the first chunk size taken from the unbounded range, it doubles with each chunk.
+	minUnboundedChunk = 1 << 4
+	// maxChunk limits the chunk size, so the workers are able to share the work until the very end.
+	maxChunk = hugeLenStep
+)
+
+// span is a range of indexes owned by a worker.
+// The owner takes chunks from the front of it while thieves steal the back half of it.
+type span struct {
+	mx     sync.Mutex
+	lf, rg int
+	// keeps the spans of different workers on different cache lines
+	_ [40]byte
+}
+
+func (s *span) set(lf, rg int) {
+	s.mx.Lock()
+	s.lf, s.rg = lf, rg
+	s.mx.Unlock()
+}
+
+// pop takes a chunk from the front of the span, its size is proportional to the remaining range
+// but not less than grain.
+func (s *span) pop(grain int) (int, int, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.lf >= s.rg {
+		return 0, 0, false
+	}
+	lf := s.lf
+	s.lf += min(min(max((s.rg-s.lf)/chunkDiv, grain), maxChunk), s.rg-s.lf)
+	return lf, s.lf, true
+}
+
+// steal takes the back half of the span.
+func (s *span) steal() (int, int, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.lf >= s.rg {
+		return 0, 0, false
+	}
+	rg := s.rg
+	s.rg = s.lf + (s.rg-s.lf)/2
+	return s.rg, rg, true
+}
+
+// plan sets how a range is split between the workers.
+type plan struct {
+	workers int
+	// chunk is the size of the chunks given out in order, 0 lets the workers steal from each other
+	chunk int
+	// grain is the minimal size of a chunk
+	grain int
+}
+
+// scheduler splits [from, limit) into chunks between the workers dynamically.
+// A bounded limit is split into equal parts claimed in order by the workers as they start or run out of work,
+// so the parts of the workers which have not started yet are evaluated in order too.
+// A worker steals from the others when there are no parts left.
+// The unbounded limit is given out from the front in growing chunks, so the evaluation stays close to the start.
+// A limit with a fixed chunk size set is given out from the front in the chunks of this size.
+type scheduler struct {
+	limit    int
+	chunk    int
+	grain    int
+	parts    [][2]int
+	claimed  atomic.Int64
+	spans    []span
+	frontier atomic.Int64
+	stopped  atomic.Bool
+	inOrder  bool
+}
+
+func newScheduler(from, limit int, pl plan) *scheduler {
+	s := &scheduler{
+		limit:   limit,
+		chunk:   pl.chunk,
+		grain:   max(pl.grain, 1),
+		spans:   make([]span, pl.workers),
+		inOrder: limit == unboundedLimit || pl.chunk > 0,
+	}
+	if s.inOrder {
+		s.frontier.Store(int64(from))
+		return s
+	}
+	s.parts = make([][2]int, pl.workers)
+	step, rem := (limit-from)/pl.workers, (limit-from)%pl.workers
+	lf := from
+	for w := range s.parts {
+		rg := lf + step
+		if w < rem {
+			rg++
+		}
+		s.parts[w] = [2]int{lf, rg}
+		lf = rg
+	}
+	return s
+}
+
+// next returns the next chunk for the worker w.
+func (s *scheduler) next(w int, unboundedChunk *int) (int, int, bool) {
+	if s.stopped.Load() {
+		return 0, 0, false
+	}
+
+	if s.inOrder {
+		size := s.chunk
+		if size == 0 {
+			size = *unboundedChunk
+			*unboundedChunk = min(size*2, maxChunk)
+		}
+		lf := int(s.frontier.Add(int64(size))) - size
+		if lf >= s.limit {
+			return 0, 0, false
+		}
+		return lf, min(lf+size, s.limit), true
+	}
+
+	if lf, rg, ok := s.spans[w].pop(s.grain); ok {
+		return lf, rg, true
+	}
+	if part := int(s.claimed.Add(1)) - 1; part < len(s.parts) {
+		s.spans[w].set(s.parts[part][0], s.parts[part][1])
+		return s.next(w, unboundedChunk)
+	}
+	for i := 1; i < len(s.spans); i++ {
+		if lf, rg, ok := s.spans[(w+i)%len(s.spans)].steal(); ok {
+			s.spans[w].set(lf, rg)
+			return s.spans[w].pop(s.grain)
+		}
+	}
+	return 0, 0, false
+}
+
+func (s *scheduler) work(w int, body func(w, lf, rg int) bool) {
+	unboundedChunk := max(minUnboundedChunk, s.grain)
+	for {
+		lf, rg, ok := s.next(w, &unboundedChunk)
+		if !ok {
+			return
+		}
+		if !body(w, lf, rg) {
+			s.stopped.Store(true)
+			return
+		}
+	}
+}
+
+// schedule evaluates body on the chunks of [from, limit) in pl.workers goroutines of ex and waits for them to finish.
+// w is the number of the worker evaluating the chunk [lf, rg); the chunks of a worker never overlap in time.
+// body returns false to stop giving out new chunks to all the workers.
+func schedule(ex *Executor, from, limit int, pl plan, body func(w, lf, rg int) bool) {
+	pl.workers = max(pl.workers, 1)
+	s := newScheduler(from, limit, pl)
+	ex.run(pl.workers, func(w int) {
+		s.work(w, body)
+	})
+}
+
+// schedule evaluates body on the chunks of the pipe in p.GoroutinesCnt goroutines of its executor.
+// The chunks of a pipe with stage boundaries or reading a source of unknown length
+// are given out in order block by block, so they stay within the look-ahead of the source.
+// An Auto pipe evaluates its first elements in the calling goroutine to plan the rest.
+func (p *Pipe[T]) schedule(body func(w, lf, rg int) bool) {
+	p.scheduleRange(0, p.limit(), body)
+}
+
+// scheduleRange evaluates body on the chunks of [from, limit) the way schedule does.
+// No more chunks are given out once the context of the pipe is done.
+func (p *Pipe[T]) scheduleRange(from, limit int, body func(w, lf, rg int) bool) {
+	body = cancelable(p.ctx, body)
+	pl := plan{workers: p.GoroutinesCnt}
+	if p.auto {
+		var ok bool
+		if from, pl, ok = p.sample(from, limit, body); !ok {
+			return
+		}
+	}
+	if len(p.stages) != 0 || (p.src != nil && !p.lenSet()) {
+		pl.chunk = stageBlock
+	}
+	schedule(p.Executor(), from, limit, pl, body)
+}
+
+// cancelable returns body stopping the evaluation once ctx is done.
+func cancelable(ctx context.Context, body func(w, lf, rg int) bool) func(w, lf, rg int) bool {
+	if ctx == nil {
+		return body
+	}
+	return func(w, lf, rg int) bool {
+		return !isDone(ctx) && body(w, lf, rg)
+	}
+}
@@ -0,0 +1,118 @@
+package internalpipe
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_schedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("each index is evaluated once", func(t *testing.T) {
+		t.Parallel()
+
+		for _, limit := range []int{0, 1, 7, 1000, 100_003} {
+			for _, workers := range []int{1, 3, 16} {
+				visited := make([]int32, limit)
+				schedule(DefaultExecutor(), 0, limit, plan{workers: workers}, func(_, lf, rg int) bool {
+					for i := lf; i < rg; i++ {
+						atomic.AddInt32(&visited[i], 1)
+					}
+					return true
+				})
+				for i := range visited {
+					require.Equal(t, int32(1), visited[i], "limit %d, workers %d, index %d", limit, workers, i)
+				}
+			}
+		}
+	})
+
+	t.Run("from and grain", func(t *testing.T) {
+		t.Parallel()
+
+		visited := make([]int32, 10_000)
+		schedule(DefaultExecutor(), 100, 10_000, plan{workers: 4, grain: 500}, func(_, lf, rg int) bool {
+			for i := lf; i < rg; i++ {
+				atomic.AddInt32(&visited[i], 1)
+			}
+			return true
+		})
+		for i := range visited {
+			expected := int32(1)
+			if i < 100 {
+				expected = 0
+			}
+			require.Equal(t, expected, visited[i], "index %d", i)
+		}
+	})
+
+	t.Run("idle workers steal the work", func(t *testing.T) {
+		t.Parallel()
+
+		var mx sync.Mutex
+		byWorker := make(map[int]int)
+		schedule(DefaultExecutor(), 0, 64, plan{workers: 4}, func(w, lf, rg int) bool {
+			// the span of the first worker is the only expensive one
+			if lf < 16 {
+				time.Sleep(time.Millisecond * time.Duration(rg-lf))
+				mx.Lock()
+				byWorker[w] += rg - lf
+				mx.Unlock()
+			}
+			return true
+		})
+		require.Greater(t, len(byWorker), 1)
+	})
+
+	t.Run("parts are evaluated in order without free goroutines", func(t *testing.T) {
+		t.Parallel()
+
+		var chunks [][2]int
+		schedule(NewExecutor(0), 0, 1000, plan{workers: 8}, func(w, lf, rg int) bool {
+			require.Equal(t, 0, w)
+			chunks = append(chunks, [2]int{lf, rg})
+			return true
+		})
+		next := 0
+		for _, ch := range chunks {
+			require.Equal(t, next, ch[0])
+			next = ch[1]
+		}
+		require.Equal(t, 1000, next)
+	})
+
+	t.Run("fixed chunks in order", func(t *testing.T) {
+		t.Parallel()
+
+		var mx sync.Mutex
+		var chunks [][2]int
+		schedule(DefaultExecutor(), 0, 1000, plan{workers: 4, chunk: 64}, func(_, lf, rg int) bool {
+			mx.Lock()
+			chunks = append(chunks, [2]int{lf, rg})
+			mx.Unlock()
+			return true
+		})
+		require.Len(t, chunks, 16)
+		for _, ch := range chunks {
+			require.Zero(t, ch[0]%64)
+			require.Equal(t, min(ch[0]+64, 1000), ch[1])
+		}
+	})
+
+	t.Run("unbounded stops", func(t *testing.T) {
+		t.Parallel()
+
+		var maxLf atomic.Int64
+		schedule(DefaultExecutor(), 0, unboundedLimit, plan{workers: 4}, func(_, lf, rg int) bool {
+			if int64(lf) > maxLf.Load() {
+				maxLf.Store(int64(lf))
+			}
+			return rg < 10_000
+		})
+		require.Less(t, maxLf.Load(), int64(10_000+4*maxChunk))
+	})
+}
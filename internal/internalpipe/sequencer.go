@@ -0,0 +1,99 @@
+package internalpipe
+
+import "sync"
+
+// sequencedChunk is the evaluated chunk [lf, rg) of a pipe, err is the error of the element it stops at.
+type sequencedChunk[C any] struct {
+	lf, rg int
+	val    C
+	err    error
+}
+
+// sequencer passes the chunks submitted out of order to emit in the order of their indexes in its own goroutine.
+// The chunks are evaluated only below the window of indexes after the first one not emitted yet.
+// The first error of emit or of a chunk stops it.
+type sequencer[C any] struct {
+	emit   func(C) error
+	window int
+
+	mx      sync.Mutex
+	cond    *sync.Cond
+	pending map[int]*sequencedChunk[C]
+	next    int
+	closed  bool
+	err     error
+	done    chan struct{}
+}
+
+func newSequencer[C any](window int, emit func(C) error) *sequencer[C] {
+	s := &sequencer[C]{
+		emit:    emit,
+		window:  window,
+		pending: make(map[int]*sequencedChunk[C]),
+		done:    make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mx)
+	go s.run()
+	return s
+}
+
+// wait waits for the chunk starting at lf to get into the window, it returns false if the sequencer has failed.
+func (s *sequencer[C]) wait(lf int) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for s.err == nil && lf >= s.next+s.window {
+		s.cond.Wait()
+	}
+	return s.err == nil
+}
+
+// submit passes the chunk [lf, rg) to the emitting goroutine, it returns false if the sequencer has failed.
+func (s *sequencer[C]) submit(lf, rg int, val C, err error) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.pending[lf] = &sequencedChunk[C]{lf: lf, rg: rg, val: val, err: err}
+	s.cond.Broadcast()
+	return s.err == nil && err == nil
+}
+
+// close waits for all the submitted chunks following each other to be emitted and returns the first error.
+func (s *sequencer[C]) close() error {
+	s.mx.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mx.Unlock()
+
+	<-s.done
+	return s.err
+}
+
+func (s *sequencer[C]) run() {
+	defer close(s.done)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for s.err == nil {
+		c, ok := s.pending[s.next]
+		if !ok {
+			if s.closed {
+				return
+			}
+			s.cond.Wait()
+			continue
+		}
+		delete(s.pending, s.next)
+		s.mx.Unlock()
+
+		err := s.emit(c.val)
+		if err == nil {
+			err = c.err
+		}
+
+		s.mx.Lock()
+		s.err = err
+		s.next = c.rg
+		s.cond.Broadcast()
+	}
+}
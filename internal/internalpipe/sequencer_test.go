@@ -0,0 +1,60 @@
+package internalpipe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sequencer_window(t *testing.T) {
+	t.Parallel()
+
+	var res []string
+	s := newSequencer(20, func(v string) error {
+		res = append(res, v)
+		return nil
+	})
+	require.True(t, s.wait(10))
+
+	waited := make(chan struct{})
+	go func() {
+		s.wait(20)
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("the chunk out of the window is not waiting")
+	default:
+	}
+
+	require.True(t, s.submit(10, 20, "10", nil))
+	require.True(t, s.submit(0, 10, "0", nil))
+	<-waited
+
+	require.NoError(t, s.close())
+	require.Equal(t, []string{"0", "10"}, res)
+}
+
+func Test_sequencer_error(t *testing.T) {
+	t.Parallel()
+
+	errChunk := errors.New("chunk failed")
+	var res []int
+	s := newSequencer(100, func(v int) error {
+		res = append(res, v)
+		return nil
+	})
+	for lf := 40; lf >= 0; lf -= 10 {
+		var err error
+		if lf == 20 {
+			err = errChunk
+		}
+		s.submit(lf, lf+10, lf, err)
+	}
+
+	require.ErrorIs(t, s.close(), errChunk)
+	require.Equal(t, []int{0, 10, 20}, res)
+	require.False(t, s.wait(50))
+	require.False(t, s.submit(50, 60, 50, nil))
+}
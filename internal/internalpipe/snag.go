@@ -19,6 +19,7 @@ type YeetSnag interface {
 
 // Yeti adds Yeti error handler to the pipe.
 // If some other handlers were set before, they are handled by the Snag
+// The errors yeeted by the source of the pipe while a terminal operation evaluates it are passed to y.
 func (p Pipe[T]) Yeti(y YeetSnag) Pipe[T] {
 	yet := y.(yeti)
 	if p.y != nil {
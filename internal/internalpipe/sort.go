@@ -4,21 +4,45 @@ import (
 	"sync"
 
 	"github.com/koss-null/funcfrog/internal/algo/parallel/qsort"
+	"github.com/koss-null/funcfrog/internal/algo/parallel/radix"
 )
 
 // Sort sorts the underlying slice on a current step of a pipeline.
 func (p Pipe[T]) Sort(less func(*T, *T) bool) Pipe[T] {
+	return p.sortWith(func(data []T) []T {
+		return qsort.Sort(data, less, p.GoroutinesCnt)
+	})
+}
+
+// SortByUint sorts the pipe by the keys with a parallel LSD radix sort keeping the order of equal keys.
+func (p Pipe[T]) SortByUint(key func(*T) uint64) Pipe[T] {
+	return p.sortWith(func(data []T) []T {
+		return radix.Sort(data, key, p.GoroutinesCnt, p.Executor().spawn)
+	})
+}
+
+// SortByString sorts the pipe by the keys with an MSD radix sort keeping the order of equal keys.
+func (p Pipe[T]) SortByString(key func(*T) string) Pipe[T] {
+	return p.sortWith(func(data []T) []T {
+		return radix.SortStrings(data, key, p.GoroutinesCnt, p.Executor().spawn)
+	})
+}
+
+// sortWith returns the pipe of the elements of p sorted by sortFn on the first evaluation.
+func (p Pipe[T]) sortWith(sortFn func([]T) []T) Pipe[T] {
 	var once sync.Once
 	var sorted []T
+	src := &bound{}
 
 	return Pipe[T]{
 		Fn: func(i int) (*T, bool) {
 			once.Do(func() {
+				defer func() { src.end(len(sorted)) }()
 				data := p.Do()
 				if len(data) == 0 {
 					return
 				}
-				sorted = qsort.Sort(data, less, p.GoroutinesCnt)
+				sorted = sortFn(data)
 			})
 			if i >= len(sorted) {
 				return nil, true
@@ -29,6 +53,11 @@ func (p Pipe[T]) Sort(less func(*T, *T) bool) Pipe[T] {
 		ValLim:        p.ValLim,
 		GoroutinesCnt: p.GoroutinesCnt,
 
-		y: p.y,
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
 	}
 }
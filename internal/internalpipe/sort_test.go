@@ -2,6 +2,8 @@ package internalpipe
 
 import (
 	"math/rand"
+	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -79,3 +81,47 @@ func Test_Sort(t *testing.T) {
 		require.Equal(t, []int{}, p)
 	})
 }
+
+func Test_SortByKey(t *testing.T) {
+	t.Parallel()
+
+	type pair struct {
+		key int
+		str string
+	}
+	a := make([]pair, 50_000)
+	for i := range a {
+		k := rand.Intn(1000)
+		a[i] = pair{key: k, str: strconv.Itoa(k)}
+	}
+
+	t.Run("uint", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a).Parallel(4).
+			SortByUint(func(x *pair) uint64 { return uint64(x.key) }).
+			Do()
+		require.Len(t, p, len(a))
+		require.True(t, sort.SliceIsSorted(p, func(i, j int) bool { return p[i].key < p[j].key }))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a).Parallel(4).
+			SortByString(func(x *pair) string { return x.str }).
+			Do()
+		require.Len(t, p, len(a))
+		require.True(t, sort.SliceIsSorted(p, func(i, j int) bool { return p[i].str < p[j].str }))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		p := Func(func(i int) (int, bool) { return i, false }).
+			Gen(10).
+			SortByUint(func(x *int) uint64 { return uint64(*x) }).
+			Do()
+		require.Equal(t, []int{}, p)
+	})
+}
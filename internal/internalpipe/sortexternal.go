@@ -0,0 +1,184 @@
+package internalpipe
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/extsort"
+)
+
+// SortExternal sorts the pipe like Sort does, but keeps at most opts.RunSize elements in memory at once.
+// The pipe is evaluated by parts of opts.RunSize indexes (or streamed if its length is not set),
+// each part is sorted in memory and written into a temporary file in opts.Dir with opts.Codec,
+// then the files are merged in p.GoroutinesCnt goroutines. The result is read lazily from the merged files.
+// Each terminal operation sorts the pipe on its first element requested and removes the files once it's over,
+// the concurrent ones share the files. The elements are not evaluated out of a terminal operation.
+// The errors of the temporary files are yeeted to the Yeti of the pipe being evaluated,
+// the elements failed to be read are skipped.
+func (p Pipe[T]) SortExternal(less func(*T, *T) bool, opts extsort.Options[T]) Pipe[T] {
+	src := &extSource[T]{p: p, less: less, opts: opts}
+	return Pipe[T]{
+		Fn:            src.get,
+		Len:           p.Len,
+		ValLim:        p.ValLim,
+		GoroutinesCnt: p.GoroutinesCnt,
+
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         src,
+		auto:        p.auto,
+		parallelSet: p.parallelSet,
+	}
+}
+
+// extSource is the source of an externally sorted pipe, it holds the sort of the terminal operations being evaluated.
+type extSource[T any] struct {
+	p    Pipe[T]
+	less func(*T, *T) bool
+	opts extsort.Options[T]
+
+	mx    sync.Mutex
+	users int
+	run   atomic.Pointer[extRun[T]]
+}
+
+// extRun is the external sort made for the terminal operations being evaluated at once.
+type extRun[T any] struct {
+	y      yeti
+	once   sync.Once
+	sorted *extsort.Sorted[T]
+	end    bound
+}
+
+func (s *extSource[T]) open(e evaluation) func() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	r := s.run.Load()
+	if r == nil {
+		r = &extRun[T]{y: e.y}
+		s.run.Store(r)
+	}
+	s.users++
+	return func() {
+		s.mx.Lock()
+		s.users--
+		last := s.users == 0
+		if last {
+			s.run.Store(nil)
+		}
+		s.mx.Unlock()
+
+		if last && r.sorted != nil {
+			// the files are removed once the last terminal operation reading them is over
+			if err := r.sorted.Close(); err != nil {
+				r.yeet(err)
+			}
+		}
+	}
+}
+
+func (s *extSource[T]) ended(i int) bool {
+	r := s.run.Load()
+	return r != nil && r.end.ended(i)
+}
+
+func (*extSource[T]) drop(int) {}
+
+func (s *extSource[T]) get(i int) (*T, bool) {
+	r := s.run.Load()
+	if r == nil {
+		return nil, true
+	}
+	r.once.Do(func() {
+		var err error
+		if r.sorted, err = s.p.sortExternal(s.less, s.opts); err != nil {
+			r.yeet(err)
+		}
+		if r.sorted == nil {
+			r.end.end(0)
+			return
+		}
+		r.end.end(r.sorted.Len())
+	})
+	if r.sorted == nil || i >= r.sorted.Len() {
+		return nil, true
+	}
+	obj, err := r.sorted.Get(i)
+	if err != nil {
+		r.yeet(err)
+		return nil, true
+	}
+	return obj, false
+}
+
+func (r *extRun[T]) yeet(err error) {
+	if r.y != nil {
+		r.y.Yeet(err)
+	}
+}
+
+func (p *Pipe[T]) sortExternal(less func(*T, *T) bool, opts extsort.Options[T]) (*extsort.Sorted[T], error) {
+	if opts.Spawn == nil {
+		opts.Spawn = p.Executor().spawn
+	}
+	s, err := extsort.New(less, p.GoroutinesCnt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	size := opts.RunSize
+	if size <= 0 {
+		size = extsort.DefaultRunSize
+	}
+	if err := p.parts(size, s.Add); err != nil {
+		s.Abort()
+		return nil, err
+	}
+	return s.Sort()
+}
+
+// parts evaluates the pipe passing its elements to add in order by parts of no more than size elements.
+func (p *Pipe[T]) parts(size int, add func([]T) error) error {
+	if !p.lenSet() {
+		defer p.open()()
+
+		var err error
+		p.stream(false, func(vals []T) {
+			if err == nil {
+				err = add(vals)
+			}
+		})
+		return err
+	}
+
+	for lf := 0; lf < p.Len; lf += size {
+		if err := add(p.part(lf, min(lf+size, p.Len)).Do()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// part returns the pipe of the elements [lf, rg) of a pipe with the length set.
+// Its errors are left for the pipe to handle.
+func (p Pipe[T]) part(lf, rg int) Pipe[T] {
+	fn, valFn, batchFn := p.Fn, p.ValFn, p.BatchFn
+	p.Fn = func(i int) (*T, bool) {
+		return fn(lf + i)
+	}
+	if valFn != nil {
+		p.ValFn = func(i int) T {
+			return valFn(lf + i)
+		}
+	}
+	if batchFn != nil {
+		p.BatchFn = func(l, r int, out []T, skipped []bool) {
+			batchFn(lf+l, lf+r, out, skipped)
+		}
+	}
+	p.Len = rg - lf
+	p.y = nil
+	return p
+}
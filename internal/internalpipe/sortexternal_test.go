@@ -0,0 +1,98 @@
+package internalpipe
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/extsort"
+)
+
+func Test_SortExternal(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 20_000)
+	for i := range a {
+		a[i] = i
+	}
+	rand.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+	less := func(x, y *int) bool { return *x < *y }
+	odd := func(x *int) bool { return *x%2 == 1 }
+
+	t.Run("length set", func(t *testing.T) {
+		t.Parallel()
+
+		for _, cnt := range []uint16{1, 7} {
+			res := Slice(a).Parallel(cnt).Filter(odd).
+				SortExternal(less, extsort.Options[int]{Dir: t.TempDir(), RunSize: 1000}).
+				Do()
+			require.Len(t, res, len(a)/2)
+			for i, x := range res {
+				require.Equal(t, 2*i+1, x)
+			}
+		}
+	})
+
+	t.Run("limit set", func(t *testing.T) {
+		t.Parallel()
+
+		for _, cnt := range []uint16{1, 7} {
+			// the elements past the limit may be evaluated ahead
+			p := Func(func(i int) (int, bool) { return a[i%len(a)], true }).Parallel(cnt).Filter(odd).Take(len(a)/2).
+				SortExternal(less, extsort.Options[int]{Dir: t.TempDir(), RunSize: 999})
+			require.Equal(t, 1, *p.First())
+			res := p.Do()
+			require.Len(t, res, len(a)/2)
+			for i, x := range res {
+				require.Equal(t, 2*i+1, x)
+			}
+		}
+	})
+
+	t.Run("parts", func(t *testing.T) {
+		t.Parallel()
+
+		var evaluated []int
+		res := Func(func(i int) (int, bool) {
+			evaluated = append(evaluated, i)
+			return i, true
+		}).Gen(len(a)).part(100, 110).Do()
+		require.Equal(t, []int{100, 101, 102, 103, 104, 105, 106, 107, 108, 109}, res)
+		require.Equal(t, res, evaluated)
+
+		p := Slice(a).SortExternal(less, extsort.Options[int]{Dir: t.TempDir(), RunSize: 10})
+		require.Equal(t, 123, p.Do()[123])
+	})
+
+	t.Run("files removed", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		p := Slice(a).Parallel(4).SortExternal(less, extsort.Options[int]{Dir: dir, RunSize: 1000})
+		require.Len(t, p.Do(), len(a))
+		require.Equal(t, 0, *p.First())
+		require.Equal(t, []int{0, 1, 2}, p.Filter(func(x *int) bool { return *x < 3 }).Parallel(4).Do())
+		// the files are removed once each terminal operation is over
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a).Parallel(4).SortExternal(less, extsort.Options[int]{Dir: "/not/existing/dir"})
+		require.NotPanics(t, func() { require.Empty(t, p.Do()) })
+
+		var errs []error
+		y := NewYeti()
+		y.Snag(func(err error) { errs = append(errs, err) })
+		res := Slice(a).Yeti(y).SortExternal(less, extsort.Options[int]{Dir: "/not/existing/dir"}).Do()
+		require.Empty(t, res)
+		require.Len(t, errs, 1)
+		require.True(t, errors.Is(errs[0], os.ErrNotExist))
+	})
+}
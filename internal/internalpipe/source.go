@@ -0,0 +1,85 @@
+package internalpipe
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrSourceReused is yeeted by a terminal operation evaluating a pipe made of a single-use source read before.
+var ErrSourceReused = errors.New("the source of the pipe has been read already")
+
+// evaluation is the terminal operation a source is opened for.
+type evaluation struct {
+	// y is the Yeti the errors of the source are yeeted to, it may be nil.
+	y yeti
+	// ex is the executor of the pipe.
+	ex *Executor
+	// ctx is the context of the pipe, it may be nil.
+	ctx context.Context
+	// workers is the amount of goroutines evaluating the pipe right after the source.
+	workers int
+}
+
+// source is the state of a pipe reading its elements from outside of it.
+// Unlike a generating function, a source knows where it ends, so a pipe with no length stops there.
+type source interface {
+	// open starts reading the source for the terminal operation e yeeting its errors to e.y,
+	// the returned function stops it.
+	open(e evaluation) func()
+	// ended reports if the source has no elements at the index i or past it.
+	ended(i int) bool
+	// drop tells the source the elements from the index i on are not needed by the terminal operation anymore.
+	drop(i int)
+}
+
+// bound is the source of the elements evaluated all at once on the first request, it ends at their amount.
+type bound struct {
+	set atomic.Bool
+	n   int
+}
+
+// boundAt returns the bound ending at n.
+func boundAt(n int) *bound {
+	b := &bound{}
+	b.end(n)
+	return b
+}
+
+// end sets the amount of the elements, it's called once.
+func (b *bound) end(n int) {
+	b.n = n
+	b.set.Store(true)
+}
+
+func (*bound) open(evaluation) func() {
+	return noop
+}
+
+func (b *bound) ended(i int) bool {
+	return b.set.Load() && i >= b.n
+}
+
+func (*bound) drop(int) {}
+
+// ended reports if the source of the pipe is set and has no elements at the index i or past it.
+func (p *Pipe[T]) ended(i int) bool {
+	return endedAt(p.src, i)
+}
+
+// endedAt reports if src is set and has no elements at the index i or past it.
+func endedAt(src source, i int) bool {
+	return src != nil && src.ended(i)
+}
+
+// drop tells the source of the pipe if it's set the elements from the index i on are not needed anymore.
+func (p *Pipe[T]) drop(i int) {
+	dropAt(p.src, i)
+}
+
+// dropAt tells src if it's set the elements from the index i on are not needed anymore.
+func dropAt(src source, i int) {
+	if src != nil {
+		src.drop(i)
+	}
+}
@@ -0,0 +1,273 @@
+package internalpipe
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// stageBlock is the amount of elements a stage boundary evaluates at once.
+const stageBlock = 1 << 6
+
+type stage interface {
+	// open starts the evaluation of the stage for a terminal operation, the returned function stops it.
+	open() func()
+	// producers returns the amount of goroutines evaluating the pipe before the stage.
+	producers() int
+}
+
+// boundary passes the elements of src to the following stages.
+// While a terminal operation is evaluated, src.GoroutinesCnt producers evaluate src ahead of the consumers
+// block by block into a bounded window of blocks. Out of the terminal operation or the window
+// the elements are evaluated by the consumers right away.
+type boundary[T any] struct {
+	src       Pipe[T]
+	consumers int
+	run       atomic.Pointer[stageRun[T]]
+}
+
+// withBoundary returns a pipe evaluated in n goroutines taking the elements of p through a boundary.
+func withBoundary[T any](p Pipe[T], n int) Pipe[T] {
+	b := &boundary[T]{src: p, consumers: n}
+	return Pipe[T]{
+		Fn:            b.get,
+		Len:           p.Len,
+		ValLim:        p.ValLim,
+		GoroutinesCnt: n,
+
+		y:           p.y,
+		ex:          p.ex,
+		ctx:         p.ctx,
+		src:         p.src,
+		stages:      append(p.stages[:len(p.stages):len(p.stages)], b),
+		atParallel:  true,
+		parallelSet: true,
+	}
+}
+
+func (b *boundary[T]) open() func() {
+	limit := unboundedLimit
+	if b.src.lenSet() {
+		limit = b.src.Len
+	}
+	producers := b.producers()
+	r := newStageRun(b.src.Fn, b.src.ended, limit, producers, 2*b.consumers+producers)
+	if !b.run.CompareAndSwap(nil, r) {
+		// the boundary is busy with another terminal operation
+		return noop
+	}
+
+	wait := b.src.Executor().start(producers, func(int) {
+		r.produce()
+	})
+	return func() {
+		r.stop()
+		wait()
+		b.run.Store(nil)
+	}
+}
+
+func (b *boundary[T]) producers() int {
+	return max(b.src.GoroutinesCnt, 1)
+}
+
+func (b *boundary[T]) get(i int) (*T, bool) {
+	if r := b.run.Load(); r != nil {
+		return r.get(i)
+	}
+	return b.src.Fn(i)
+}
+
+type stageBlockEvs[T any] struct {
+	evs      []ev[T]
+	ready    bool
+	consumed int
+}
+
+// stageRun is the state of a boundary during a terminal operation.
+// The blocks are claimed for evaluation in order, at most window blocks after the lowest not consumed one.
+type stageRun[T any] struct {
+	fn GeneratorFn[T]
+	// ended reports if the source of the stage has ended at an index, the producers stop there
+	ended  func(int) bool
+	limit  int
+	window int
+	slots  chan struct{}
+
+	mx      sync.Mutex
+	cond    *sync.Cond
+	blocks  map[int]*stageBlockEvs[T]
+	next    int
+	floor   int
+	stopped bool
+}
+
+func newStageRun[T any](fn GeneratorFn[T], ended func(int) bool, limit, producers, window int) *stageRun[T] {
+	r := &stageRun[T]{
+		fn:     fn,
+		ended:  ended,
+		limit:  limit,
+		window: window,
+		slots:  make(chan struct{}, producers),
+		blocks: make(map[int]*stageBlockEvs[T], window),
+	}
+	r.cond = sync.NewCond(&r.mx)
+	return r
+}
+
+func (r *stageRun[T]) stop() {
+	r.mx.Lock()
+	r.stopped = true
+	r.mx.Unlock()
+	r.cond.Broadcast()
+}
+
+// produce evaluates the blocks one by one while there is a room for them in the window.
+func (r *stageRun[T]) produce() {
+	r.mx.Lock()
+	for {
+		for !r.stopped && r.next >= r.floor+r.window {
+			r.cond.Wait()
+		}
+		if r.stopped || r.next*stageBlock >= r.limit || r.ended(r.next*stageBlock) {
+			r.mx.Unlock()
+			return
+		}
+		k := r.claim()
+		r.mx.Unlock()
+
+		r.evaluate(k)
+		r.mx.Lock()
+	}
+}
+
+// claim takes the next block for evaluation, r.mx must be locked.
+func (r *stageRun[T]) claim() int {
+	k := r.next
+	r.next++
+	r.blocks[k] = &stageBlockEvs[T]{}
+	return k
+}
+
+// evaluate evaluates the block k, at most len(r.slots) blocks are evaluated at once.
+func (r *stageRun[T]) evaluate(k int) {
+	r.slots <- struct{}{}
+	lf := k * stageBlock
+	evs := make([]ev[T], min(stageBlock, r.limit-lf))
+	for j := range evs {
+		evs[j].obj, evs[j].skipped = r.fn(lf + j)
+	}
+	<-r.slots
+
+	r.mx.Lock()
+	b := r.blocks[k]
+	b.evs, b.ready = evs, true
+	r.mx.Unlock()
+	r.cond.Broadcast()
+}
+
+// get returns the element i waiting for its block to be evaluated.
+// If the block is not claimed yet, the consumer evaluates the blocks up to it by itself.
+func (r *stageRun[T]) get(i int) (*T, bool) {
+	k := i / stageBlock
+
+	r.mx.Lock()
+	for {
+		if r.stopped || i >= r.limit || k < r.floor || k >= r.floor+r.window {
+			r.mx.Unlock()
+			return r.fn(i)
+		}
+		if k >= r.next {
+			next := r.claim()
+			r.mx.Unlock()
+			r.evaluate(next)
+			r.mx.Lock()
+			continue
+		}
+		if b := r.blocks[k]; b.ready {
+			e := b.evs[i-k*stageBlock]
+			b.consumed++
+			r.advance()
+			r.mx.Unlock()
+			return e.obj, e.skipped
+		}
+		r.cond.Wait()
+	}
+}
+
+// advance drops the consumed blocks from the front of the window, r.mx must be locked.
+func (r *stageRun[T]) advance() {
+	floor := r.floor
+	for {
+		b, ok := r.blocks[r.floor]
+		if !ok || !b.ready || b.consumed < len(b.evs) {
+			break
+		}
+		delete(r.blocks, r.floor)
+		r.floor++
+	}
+	if r.floor != floor {
+		r.cond.Broadcast()
+	}
+}
+
+// open starts the evaluation of the source and the stage boundaries of the pipe, the returned function stops it
+// and yeets the error of the context of the pipe if it's done.
+func (p *Pipe[T]) open() func() {
+	if len(p.stages) == 0 && p.ctx == nil && p.src == nil {
+		return noop
+	}
+
+	closeSrc := noop
+	if p.src != nil {
+		closeSrc = p.src.open(evaluation{y: p.y, ex: p.Executor(), ctx: p.ctx, workers: p.sourceWorkers()})
+	}
+	closers := make([]func(), len(p.stages))
+	for i, s := range p.stages {
+		closers[i] = s.open()
+	}
+	ctx, y, src := p.ctx, p.y, p.src
+	unwatch := noop
+	if ctx != nil && src != nil {
+		// the evaluation waiting for the source is released once the context is done
+		unwatch = onDone(ctx, func() { src.drop(0) })
+	}
+	return func() {
+		unwatch()
+		// nothing waits for the source past the terminal operation
+		dropAt(src, 0)
+		// the following stages are stopped first since their producers may wait for the previous ones
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+		closeSrc()
+		if isDone(ctx) && y != nil {
+			y.Yeet(ctx.Err())
+		}
+	}
+}
+
+// sourceWorkers returns the amount of goroutines evaluating the pipe right after its source.
+func (p *Pipe[T]) sourceWorkers() int {
+	if len(p.stages) != 0 {
+		return p.stages[0].producers()
+	}
+	return max(p.GoroutinesCnt, 1)
+}
+
+func noop() {}
+
+// onDone calls fn in its own goroutine once ctx is done, the returned function stops waiting for it.
+func onDone(ctx context.Context, fn func()) func() {
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			fn()
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+	}
+}
@@ -0,0 +1,153 @@
+package internalpipe
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type concurrency struct {
+	cur, max atomic.Int64
+}
+
+func (c *concurrency) enter() {
+	cur := c.cur.Add(1)
+	for prev := c.max.Load(); cur > prev && !c.max.CompareAndSwap(prev, cur); {
+		prev = c.max.Load()
+	}
+}
+
+func (c *concurrency) leave() {
+	c.cur.Add(-1)
+}
+
+func Test_Parallel_stages(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = i
+	}
+
+	t.Run("boundaries", func(t *testing.T) {
+		t.Parallel()
+
+		p := Slice(a).Parallel(2).Parallel(3)
+		require.Equal(t, 2, p.GoroutinesCnt)
+		require.Empty(t, p.stages)
+
+		p = p.Map(func(x int) int { return x }).Parallel(5).Parallel(6)
+		require.Equal(t, 5, p.GoroutinesCnt)
+		require.Len(t, p.stages, 1)
+
+		p = p.Filter(func(*int) bool { return true }).Parallel(1)
+		require.Equal(t, 1, p.GoroutinesCnt)
+		require.Len(t, p.stages, 2)
+	})
+
+	t.Run("each stage is evaluated on its own goroutines", func(t *testing.T) {
+		t.Parallel()
+
+		var cpu, io concurrency
+		p := Slice(a).Parallel(2).
+			Map(func(x int) int {
+				cpu.enter()
+				defer cpu.leave()
+				return x * 2
+			}).
+			Parallel(8).
+			Map(func(x int) int {
+				io.enter()
+				defer io.leave()
+				return x + 1
+			})
+
+		res := p.Do()
+		require.Len(t, res, len(a))
+		for i, x := range res {
+			require.Equal(t, 2*i+1, x)
+		}
+		require.LessOrEqual(t, cpu.max.Load(), int64(2))
+		require.LessOrEqual(t, io.max.Load(), int64(8))
+	})
+
+	t.Run("an explicit single goroutine stage", func(t *testing.T) {
+		t.Parallel()
+
+		var single, wide concurrency
+		p := Slice(a).Parallel(1).
+			Map(func(x int) int {
+				single.enter()
+				defer single.leave()
+				if x%100 == 0 {
+					time.Sleep(100 * time.Microsecond)
+				}
+				return x
+			}).
+			Parallel(8).
+			Map(func(x int) int {
+				wide.enter()
+				defer wide.leave()
+				time.Sleep(time.Microsecond)
+				return x
+			})
+		require.Equal(t, a, p.Do())
+		require.Equal(t, int64(1), single.max.Load())
+		require.LessOrEqual(t, wide.max.Load(), int64(8))
+		require.Greater(t, wide.max.Load(), int64(1))
+	})
+
+	t.Run("terminals", func(t *testing.T) {
+		t.Parallel()
+
+		odd := func(x *int) bool { return *x%2 == 1 }
+		p := Slice(a).Parallel(3).Filter(odd).Parallel(4).Map(func(x int) int { return x * 10 })
+
+		require.Equal(t, len(a)/2, p.Count())
+		require.Equal(t, 10*(len(a)/2)*(len(a)/2), p.Sum(func(x, y *int) int { return *x + *y }))
+		require.Equal(t, 10, *p.First())
+		require.NotNil(t, p.Any())
+		require.Equal(t, 10*(len(a)-1), *p.Max(func(x, y *int) bool { return *x < *y }))
+
+		proms := p.Promices()
+		x, ok := proms[3]()
+		require.True(t, ok)
+		require.Equal(t, 30, x)
+	})
+
+	t.Run("no length", func(t *testing.T) {
+		t.Parallel()
+
+		res := Func(func(i int) (int, bool) { return i, true }).
+			Parallel(2).
+			Filter(func(x *int) bool { return *x%3 == 0 }).
+			Parallel(4).
+			Map(func(x int) int { return x / 3 }).
+			Take(1000).
+			Do()
+		require.Len(t, res, 1000)
+		for i, x := range res {
+			require.Equal(t, i, x)
+		}
+	})
+
+	t.Run("several boundaries without free goroutines", func(t *testing.T) {
+		t.Parallel()
+
+		ex := NewExecutor(0)
+		res := Slice(a).WithExecutor(ex).Parallel(2).
+			Map(func(x int) int { return x + 1 }).
+			Parallel(3).
+			Map(func(x int) int { return x * 2 }).
+			Parallel(4).
+			Filter(func(x *int) bool { return *x%4 == 0 }).
+			Do()
+		require.Len(t, res, len(a)/2)
+		for i, x := range res {
+			require.Equal(t, 4*(i+1), x)
+		}
+		require.Zero(t, ex.Metrics().Submitted)
+	})
+}
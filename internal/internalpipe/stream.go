@@ -0,0 +1,237 @@
+package internalpipe
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// streamChunk is the evaluated chunk [lf, rg) of a stream.
+type streamChunk[T any] struct {
+	lf, rg int
+	vals   []T
+	cnt    int
+	// last is set if the source of the pipe ends within the chunk
+	last bool
+	// apply merges the first take elements of the chunk into the result instead of its values if it's set
+	apply func(take int)
+}
+
+// streamMerge merges the chunks evaluated out of order in the order of their indexes.
+// It is done when limit elements are found or the source of the pipe ends.
+// The chunks are evaluated only below budget: the index the elements up to the limit are expected to be found by.
+type streamMerge[T any] struct {
+	mx         sync.Mutex
+	cond       *sync.Cond
+	pending    map[int]*streamChunk[T]
+	next       int
+	budget     int
+	res        []T
+	cnt        int
+	limit      int
+	needResult bool
+	sink       func([]T)
+	done       atomic.Bool
+}
+
+func newStreamMerge[T any](limit int, needResult bool, sink func([]T)) *streamMerge[T] {
+	m := &streamMerge[T]{
+		pending:    make(map[int]*streamChunk[T]),
+		budget:     limit,
+		limit:      limit,
+		needResult: needResult || sink != nil,
+		sink:       sink,
+	}
+	m.cond = sync.NewCond(&m.mx)
+	return m
+}
+
+// add merges c and all the pending chunks following it.
+func (m *streamMerge[T]) add(c *streamChunk[T]) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.pending[c.lf] = c
+	merged := false
+	for !m.done.Load() {
+		c, ok := m.pending[m.next]
+		if !ok {
+			break
+		}
+		delete(m.pending, m.next)
+		m.next = c.rg
+		m.merge(c)
+		merged = true
+	}
+	if merged {
+		m.budget = streamBudget(m.next, m.cnt, m.limit)
+		m.cond.Broadcast()
+	}
+}
+
+// merge appends the elements of the next chunk c, m.mx must be locked.
+func (m *streamMerge[T]) merge(c *streamChunk[T]) {
+	take := min(c.cnt, m.limit-m.cnt)
+	switch {
+	case take == 0:
+	case c.apply != nil:
+		c.apply(take)
+	case m.sink != nil:
+		m.sink(c.vals[:take])
+	case m.needResult:
+		m.res = append(m.res, c.vals[:take]...)
+	}
+	m.cnt += take
+	if m.cnt == m.limit || c.last {
+		m.done.Store(true)
+	}
+}
+
+// wait waits for the budget to pass the index lf and returns it, it returns false if the stream is done.
+func (m *streamMerge[T]) wait(lf int) (int, bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	for !m.done.Load() && m.budget <= lf {
+		m.cond.Wait()
+	}
+	return m.budget, !m.done.Load()
+}
+
+// streamBudget returns the index the limit elements are expected to be found by
+// after cnt of them are found among the first next elements.
+// Its distance from next is at least the amount of elements left and at most next,
+// so a pipe which doesn't skip is never evaluated past the limit.
+func streamBudget(next, cnt, limit int) int {
+	if limit == unboundedLimit {
+		return unboundedLimit
+	}
+	need := limit - cnt
+	ahead := next
+	if cnt != 0 {
+		// the amount of indexes expected to hold the rest of the elements at the current ratio
+		ahead = min(ahead, int(float64(need)*float64(next)/float64(cnt))+1)
+	}
+	if ahead = max(ahead, need); ahead >= unboundedLimit-next {
+		return unboundedLimit
+	}
+	return next + ahead
+}
+
+// stream evaluates the pipe of unknown length until p.ValLim elements are found (or all of them if it's not set)
+// or the source of the pipe ends. A pipe with neither a limit nor a source is evaluated endlessly.
+// The chunks are evaluated in order in p.GoroutinesCnt goroutines ahead of the merge up to the budget
+// and merged into a growing result. Once the limit is reached, the chunks being evaluated are dropped.
+// The result is the same as the one of the evaluation one by one.
+// If sink is set, the elements are passed to it in order instead of being gathered into the result.
+func (p *Pipe[T]) stream(needResult bool, sink func([]T)) ([]T, int) {
+	limit := unboundedLimit
+	if p.limitSet() {
+		limit = p.ValLim
+	}
+	if limit == 0 {
+		return []T{}, 0
+	}
+
+	if p.GoroutinesCnt == 1 && !p.auto && len(p.stages) == 0 && p.ctx == nil {
+		return streamSingleThread(p, limit, needResult, sink)
+	}
+
+	m := newStreamMerge(limit, needResult, sink)
+	fn, src := p.Fn, p.src
+	p.scheduleRange(0, unboundedLimit, m.body(src, func(lf, rg int) *streamChunk[T] {
+		return m.eval(fn, src, lf, rg)
+	}))
+
+	if m.res == nil {
+		m.res = []T{}
+	}
+	return m.res, m.cnt
+}
+
+// body returns the scheduler body evaluating a chunk with eval reading src and merging it.
+// The part of the chunk past the budget is evaluated after the budget grows.
+func (m *streamMerge[T]) body(src source, eval func(lf, rg int) *streamChunk[T]) func(w, lf, rg int) bool {
+	return func(_, lf, rg int) bool {
+		for lf < rg {
+			budget, ok := m.wait(lf)
+			if !ok {
+				return false
+			}
+			c := eval(lf, min(rg, budget))
+			m.add(c)
+			lf = c.rg
+		}
+		if m.done.Load() {
+			// the chunks being evaluated are dropped, so they don't wait for the source
+			dropAt(src, 0)
+			return false
+		}
+		return true
+	}
+}
+
+// eval evaluates the chunk [lf, rg) of fn until the stream is done or src ends.
+func (m *streamMerge[T]) eval(fn GeneratorFn[T], src source, lf, rg int) *streamChunk[T] {
+	c := &streamChunk[T]{lf: lf, rg: rg}
+	return m.scan(c, fn, src, func(_ int, obj *T) {
+		if m.needResult {
+			c.vals = append(c.vals, *obj)
+		}
+	})
+}
+
+// scan passes the not skipped elements of the chunk c of fn to each along with their indexes
+// until the stream is done or src ends.
+func (m *streamMerge[T]) scan(c *streamChunk[T], fn GeneratorFn[T], src source, each func(int, *T)) *streamChunk[T] {
+	for j := c.lf; j < c.rg && !m.done.Load(); j++ {
+		if endedAt(src, j) {
+			c.last = true
+			break
+		}
+		obj, skipped := fn(j)
+		if skipped {
+			continue
+		}
+		each(j, obj)
+		c.cnt++
+	}
+	c.last = c.last || endedAt(src, c.rg)
+	return c
+}
+
+// searchLimit returns the limit of indexes to look for a single element in.
+// A pipe with no length is searched until the element is found or its source ends.
+func (p *Pipe[T]) searchLimit() int {
+	switch {
+	case p.lenSet():
+		return p.Len
+	case p.ValLim == 0:
+		return 0
+	default:
+		return unboundedLimit
+	}
+}
+
+func streamSingleThread[T any](p *Pipe[T], limit int, needResult bool, sink func([]T)) ([]T, int) {
+	res := []T{}
+	cnt := 0
+	for i := 0; cnt < limit && i < unboundedLimit && !p.ended(i); i++ {
+		obj, skipped := p.Fn(i)
+		if skipped {
+			continue
+		}
+		if needResult || sink != nil {
+			res = append(res, *obj)
+		}
+		if sink != nil && len(res) == batchBlock {
+			sink(res)
+			res = res[:0]
+		}
+		cnt++
+	}
+	if sink != nil && len(res) != 0 {
+		sink(res)
+		res = res[:0]
+	}
+	return res, cnt
+}
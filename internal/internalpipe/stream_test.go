@@ -0,0 +1,144 @@
+package internalpipe
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// finite returns the pipe of [0, n) with no length and the source ending at n.
+func finite(n int) Pipe[int] {
+	p := Func(func(i int) (int, bool) { return i, i < n })
+	p.src = boundAt(n)
+	return p
+}
+
+func Test_stream(t *testing.T) {
+	t.Parallel()
+
+	source := finite
+	even := func(x *int) bool { return *x%2 == 0 }
+
+	for _, cnt := range []uint16{1, 7} {
+		cnt := cnt
+		t.Run("no limit "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			p := source(100_000).Filter(even).Parallel(cnt)
+			res := p.Do()
+			require.Len(t, res, 50_000)
+			for i, x := range res {
+				require.Equal(t, 2*i, x)
+			}
+			require.Equal(t, 50_000, p.Count())
+			require.Equal(t, 2*50_000*49_999/2, p.Sum(func(x, y *int) int { return *x + *y }))
+		})
+
+		t.Run("limit "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			p := Func(func(i int) (int, bool) { return i, true }).
+				Filter(func(x *int) bool { return *x%7 == 3 }).
+				Parallel(cnt).
+				Take(10_000)
+			res := p.Do()
+			require.Len(t, res, 10_000)
+			for i, x := range res {
+				require.Equal(t, 7*i+3, x)
+			}
+			require.Equal(t, 10_000, p.Count())
+		})
+
+		t.Run("limit past the end of the source "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			p := source(10).Parallel(cnt).Take(11)
+			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, p.Do())
+			// the elements are counted up to the end of the source
+			require.Equal(t, 10, p.Count())
+		})
+
+		t.Run("not evaluated past the limit "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			var maxIdx atomic.Int64
+			p := Func(func(i int) (int, bool) {
+				for prev := maxIdx.Load(); int64(i) > prev && !maxIdx.CompareAndSwap(prev, int64(i)); {
+					prev = maxIdx.Load()
+				}
+				return i, true
+			}).Parallel(cnt).Take(1000)
+			require.Len(t, p.Do(), 1000)
+			require.Equal(t, int64(999), maxIdx.Load())
+		})
+
+		t.Run("single element "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			p := source(5000).Filter(func(x *int) bool { return *x == 4321 }).Parallel(cnt)
+			require.Equal(t, 4321, *p.First())
+			require.Equal(t, 4321, *p.Any())
+			require.Nil(t, source(0).Parallel(cnt).First())
+			require.Nil(t, source(0).Parallel(cnt).Any())
+			require.Empty(t, source(0).Parallel(cnt).Do())
+		})
+
+		t.Run("far elements "+strconv.Itoa(int(cnt)), func(t *testing.T) {
+			t.Parallel()
+
+			id := func(i int) (int, bool) { return i, true }
+			p := Func(id).Filter(func(x *int) bool { return *x > 3_000_000 }).Parallel(cnt)
+			require.Equal(t, 3_000_001, *p.First())
+			require.Greater(t, *p.Any(), 3_000_000)
+			res := Func(id).Filter(func(x *int) bool { return *x%2_000_000 == 1 }).Parallel(cnt).Take(3).Do()
+			require.Equal(t, []int{1, 2_000_001, 4_000_001}, res)
+		})
+	}
+
+	t.Run("limit evaluated in parallel", func(t *testing.T) {
+		t.Parallel()
+
+		var c concurrency
+		p := Func(func(i int) (int, bool) {
+			c.enter()
+			defer c.leave()
+			time.Sleep(100 * time.Microsecond)
+			return i, true
+		}).Filter(func(x *int) bool { return *x%5 == 0 }).Take(100)
+
+		seq := p.Do()
+		require.Equal(t, int64(1), c.max.Load())
+		require.Equal(t, seq, p.Parallel(4).Do())
+		require.Greater(t, c.max.Load(), int64(1))
+	})
+
+	t.Run("stages", func(t *testing.T) {
+		t.Parallel()
+
+		res := source(10_000).Parallel(2).Filter(even).Parallel(3).Map(func(x int) int { return x / 2 }).Do()
+		require.Len(t, res, 5000)
+		for i, x := range res {
+			require.Equal(t, i, x)
+		}
+	})
+}
+
+func Test_streamBudget(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, unboundedLimit, streamBudget(100, 10, unboundedLimit))
+	// nothing is skipped: exactly the rest of the elements
+	require.Equal(t, 1000, streamBudget(100, 100, 1000))
+	// a half of the elements is skipped: 50 more indexes hold the rest 25 elements
+	require.Equal(t, 151, streamBudget(100, 50, 75))
+	// nothing is found yet: twice as much as evaluated
+	require.Equal(t, 200, streamBudget(100, 0, 10))
+	// at most twice as much as evaluated
+	require.Equal(t, 200, streamBudget(100, 1, 10))
+	// the limit close to math.MaxInt doesn't overflow
+	require.Equal(t, unboundedLimit, streamBudget(100, 100, math.MaxInt))
+}
@@ -1,7 +1,5 @@
 package internalpipe
 
-import "sync"
-
 func sumSingleThread[T any](length int, plus AccumFn[T], fn GeneratorFn[T]) T {
 	var res T
 	var obj *T
@@ -25,46 +23,72 @@ func sumSingleThread[T any](length int, plus AccumFn[T], fn GeneratorFn[T]) T {
 	return res
 }
 
+func sumValuesSingleThread[T any](length int, plus AccumFn[T], valFn func(int) T) T {
+	if length == 0 {
+		var res T
+		return res
+	}
+	res := valFn(0)
+	var val T
+	for i := 1; i < length; i++ {
+		val = valFn(i)
+		res = plus(&res, &val)
+	}
+	return res
+}
+
 // Sum returns the sum of all elements. It is similar to Reduce but is able to work in parallel.
+// A pipe with no length is summed up in order starting from the zero value the way each part of it is.
 func (p Pipe[T]) Sum(plus AccumFn[T]) T {
-	length := p.limit()
-	if p.GoroutinesCnt == 1 {
-		return sumSingleThread(length, plus, p.Fn)
+	if !p.lenSet() {
+		return Fold(
+			p,
+			func() (zero T) { return zero },
+			func(res T, _ int, x *T) T { return plus(&res, x) },
+			func(a, b T) T { return plus(&a, &b) },
+		)
 	}
+	defer p.open()()
 
-	var (
-		step = divUp(length, p.GoroutinesCnt)
-
-		res   T
-		resMx sync.Mutex
-		wg    sync.WaitGroup
-	)
-
-	sum := func(x *T) {
-		resMx.Lock()
-		res = plus(&res, x)
-		resMx.Unlock()
+	length := p.limit()
+	vals := p.ValFn != nil && p.lenSet()
+	switch {
+	case p.BatchFn != nil && p.lenSet() && !vals:
+		return p.sumBatch(plus)
+	case p.GoroutinesCnt == 1 && vals:
+		return sumValuesSingleThread(length, plus, p.ValFn)
+	case p.GoroutinesCnt == 1:
+		return sumSingleThread(length, plus, p.Fn)
 	}
 
-	tickets := genTickets(p.GoroutinesCnt)
-	for lf := 0; lf < length; lf += step {
-		wg.Add(1)
-		<-tickets
-		go func(lf, rg int) {
-			var inRes T
-			var obj *T
-			var skipped bool
+	// each worker sums up all of its chunks into its own part
+	parts := make([]T, p.GoroutinesCnt)
+	p.schedule(func(w, lf, rg int) bool {
+		var inRes T
+		if vals {
+			var val T
 			for i := lf; i < rg; i++ {
-				if obj, skipped = p.Fn(i); !skipped {
-					inRes = plus(&inRes, obj)
-				}
+				val = p.ValFn(i)
+				inRes = plus(&inRes, &val)
 			}
-			sum(&inRes)
-			wg.Done()
-			tickets <- struct{}{}
-		}(lf, min(lf+step, length))
-	}
-	wg.Wait()
+			parts[w] = plus(&parts[w], &inRes)
+			return true
+		}
 
+		var obj *T
+		var skipped bool
+		for i := lf; i < rg; i++ {
+			if obj, skipped = p.Fn(i); !skipped {
+				inRes = plus(&inRes, obj)
+			}
+		}
+		parts[w] = plus(&parts[w], &inRes)
+		return true
+	})
+
+	var res T
+	for i := range parts {
+		res = plus(&res, &parts[i])
+	}
 	return res
 }
@@ -0,0 +1,182 @@
+package internalpipe
+
+import (
+	"io/fs"
+	"path"
+)
+
+// FileEntry is an entry of a file system visited by Walk.
+// Its Info is read lazily by the goroutine calling it, so the files are stat in parallel by a following stage.
+type FileEntry struct {
+	// Path is the slash-separated path of the entry in its file system the way fs.WalkDir passes it.
+	Path string
+	fs.DirEntry
+	// Data is the content of the file set by ReadFile.
+	Data []byte
+	// Err is the error of reading the file set by ReadFile.
+	Err error
+
+	fsys fs.FS
+}
+
+// Open opens the file of the entry.
+func (e FileEntry) Open() (fs.File, error) {
+	return e.fsys.Open(e.Path)
+}
+
+// ReadFile returns e with the content of its file read into Data or the error of reading it set to Err.
+func ReadFile(e FileEntry) FileEntry {
+	e.Data, e.Err = fs.ReadFile(e.fsys, e.Path)
+	return e
+}
+
+// Walk creates a pipe of the entries of the file tree of fsys rooted at root, the length is unknown.
+// It visits the entries in the lexical order fs.WalkDir does, so a directory is given out before its entries
+// and the entry at an index is the same for each evaluation. The directories are read ahead of the pipe
+// in parallel on the executor of the pipe, see walker. The symbolic links are not followed.
+// The errors of reading the directories are yeeted to the Yeti of the pipe, the entries read before stay in it.
+func Walk(fsys fs.FS, root string) Pipe[FileEntry] {
+	y := newRelay()
+	w := &walker{fsys: fsys, root: root, y: y}
+	return sequenceWith(y, w.next, seqHooks{start: w.prepare, release: w.stop})
+}
+
+// walker walks a file tree in the order of fs.WalkDir by the goroutine reading the pipe.
+// Once the entries of a directory are listed, its subdirectories are read ahead on the executor of the pipe,
+// at most 2*workers of them at once; a directory is read by the goroutine reaching it if it's not read by then.
+// The directories read ahead are dropped or waited for once the terminal operation is over.
+type walker struct {
+	fsys    fs.FS
+	root    string
+	y       yeti
+	ex      *Executor
+	workers int
+
+	started bool
+	// stack is the directories being walked, the innermost one is the last
+	stack []*walkDir
+	// ahead is the amount of the directories read ahead and not reached yet
+	ahead int
+}
+
+// walkDir is a directory being walked.
+type walkDir struct {
+	path    string
+	read    *dirRead
+	listed  bool
+	entries []fs.DirEntry
+	// reads are the reads of the subdirectories started ahead by the indexes of their entries
+	reads map[int]*dirRead
+	next  int
+}
+
+// dirRead is the reading of a directory, it's done by a goroutine of the executor if one starts it.
+type dirRead struct {
+	entries []fs.DirEntry
+	err     error
+	done    bool
+	wait    func()
+}
+
+// prepare takes the executor and the amount of the goroutines of the terminal operation walking the tree.
+func (w *walker) prepare(e evaluation) {
+	w.ex, w.workers = e.ex, e.workers
+}
+
+// next returns the next entry, it returns false at the end of the tree.
+func (w *walker) next() (FileEntry, bool) {
+	if !w.started {
+		w.started = true
+		info, err := fs.Stat(w.fsys, w.root)
+		if err != nil {
+			w.y.Yeet(err)
+			return FileEntry{}, false
+		}
+		d := fs.FileInfoToDirEntry(info)
+		if d.IsDir() {
+			w.stack = append(w.stack, &walkDir{path: w.root})
+		}
+		return FileEntry{Path: w.root, DirEntry: d, fsys: w.fsys}, true
+	}
+
+	for len(w.stack) != 0 {
+		dir := w.stack[len(w.stack)-1]
+		if !dir.listed {
+			w.list(dir)
+		}
+		if dir.next == len(dir.entries) {
+			w.stack = w.stack[:len(w.stack)-1]
+			continue
+		}
+
+		i := dir.next
+		dir.next++
+		d := dir.entries[i]
+		p := path.Join(dir.path, d.Name())
+		if d.IsDir() {
+			sub := &walkDir{path: p, read: dir.reads[i]}
+			delete(dir.reads, i)
+			w.stack = append(w.stack, sub)
+		}
+		return FileEntry{Path: p, DirEntry: d, fsys: w.fsys}, true
+	}
+	return FileEntry{}, false
+}
+
+// list takes the entries of the directory read ahead or reads them right away,
+// then starts reading its subdirectories ahead while there is a room for them.
+func (w *walker) list(dir *walkDir) {
+	dir.listed = true
+	if r := dir.read; r != nil {
+		w.ahead--
+		r.wait()
+		if !r.done {
+			r.entries, r.err = fs.ReadDir(w.fsys, dir.path)
+		}
+		dir.entries, dir.read = r.entries, nil
+		if r.err != nil {
+			w.y.Yeet(r.err)
+		}
+	} else {
+		var err error
+		if dir.entries, err = fs.ReadDir(w.fsys, dir.path); err != nil {
+			w.y.Yeet(err)
+		}
+	}
+
+	if w.ex == nil || w.workers < 2 {
+		return
+	}
+	for i, d := range dir.entries {
+		if w.ahead == 2*w.workers {
+			return
+		}
+		if !d.IsDir() {
+			continue
+		}
+		if dir.reads == nil {
+			dir.reads = make(map[int]*dirRead)
+		}
+		r := &dirRead{}
+		p := path.Join(dir.path, d.Name())
+		r.wait = w.ex.start(1, func(int) {
+			r.entries, r.err = fs.ReadDir(w.fsys, p)
+			r.done = true
+		})
+		dir.reads[i] = r
+		w.ahead++
+	}
+}
+
+// stop drops the reads ahead not started yet and waits for the started ones.
+func (w *walker) stop() {
+	for _, dir := range w.stack {
+		if dir.read != nil {
+			dir.read.wait()
+		}
+		for _, r := range dir.reads {
+			r.wait()
+		}
+	}
+	w.stack = nil
+}
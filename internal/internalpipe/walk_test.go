@@ -0,0 +1,185 @@
+package internalpipe
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// walkTree makes a file system of nested directories with a couple of files in each.
+func walkTree() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 0; i < 20; i++ {
+		dir := "d" + strconv.Itoa(i)
+		for j := 0; j < 5; j++ {
+			sub := dir + "/s" + strconv.Itoa(j)
+			fsys[sub+"/a.txt"] = &fstest.MapFile{Data: []byte(sub + "/a")}
+			fsys[sub+"/b.log"] = &fstest.MapFile{Data: []byte(sub + "/b")}
+		}
+		fsys[dir+"/empty"] = &fstest.MapFile{Mode: fs.ModeDir}
+	}
+	fsys["top.txt"] = &fstest.MapFile{Data: []byte("top")}
+	return fsys
+}
+
+func walkDirPaths(t *testing.T, fsys fs.FS, root string) []string {
+	var paths []string
+	require.NoError(t, fs.WalkDir(fsys, root, func(path string, _ fs.DirEntry, err error) error {
+		paths = append(paths, path)
+		return err
+	}))
+	sort.Strings(paths)
+	return paths
+}
+
+func entryPaths(entries []FileEntry) []string {
+	paths := make([]string, len(entries))
+	for i := range entries {
+		paths[i] = entries[i].Path
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func Test_Walk(t *testing.T) {
+	t.Parallel()
+
+	fsys := walkTree()
+	for _, root := range []string{".", "d3", "d3/s1", "top.txt"} {
+		exp := walkDirPaths(t, fsys, root)
+		t.Run(root, func(t *testing.T) {
+			require.Equal(t, exp, entryPaths(Walk(fsys, root).Take(math.MaxInt).Do()))
+			require.Equal(t, exp, entryPaths(Walk(fsys, root).Parallel(8).Take(math.MaxInt).Do()))
+		})
+	}
+
+	t.Run("entries", func(t *testing.T) {
+		var exp []string
+		require.NoError(t, fs.WalkDir(fsys, ".", func(path string, _ fs.DirEntry, _ error) error {
+			exp = append(exp, path)
+			return nil
+		}))
+		entries := Walk(fsys, ".").Parallel(4).Do()
+		// the entries come in the order of fs.WalkDir
+		require.Equal(t, exp, unsortedPaths(entries))
+		require.Equal(t, exp[:20], unsortedPaths(Walk(fsys, ".").Parallel(4).Take(20).Do()))
+		for _, e := range entries {
+			info, err := e.Info()
+			require.NoError(t, err)
+			require.Equal(t, e.IsDir(), info.IsDir())
+		}
+	})
+	t.Run("executor", func(t *testing.T) {
+		var exp []string
+		require.NoError(t, fs.WalkDir(fsys, ".", func(path string, _ fs.DirEntry, _ error) error {
+			exp = append(exp, path)
+			return nil
+		}))
+		// the directories not read ahead are read by the goroutine reaching them
+		ex := NewExecutor(0)
+		require.Equal(t, exp, unsortedPaths(Walk(fsys, ".").Parallel(4).WithExecutor(ex).Do()))
+
+		ex = NewExecutor(4)
+		defer ex.Close()
+		require.Equal(t, exp, unsortedPaths(Walk(fsys, ".").WithExecutor(ex).Do()))
+		require.Zero(t, ex.Metrics().Submitted)
+		require.Equal(t, exp, unsortedPaths(Walk(fsys, ".").Parallel(4).WithExecutor(ex).Do()))
+		require.NotZero(t, ex.Metrics().Submitted)
+	})
+	t.Run("stopped", func(t *testing.T) {
+		counting := &countingDirFS{MapFS: fsys}
+		res := Walk(counting, ".").Parallel(4).Take(5).Do()
+		require.Len(t, res, 5)
+		// the walk stops once the terminal operation is over
+		require.Less(t, counting.reads.Load(), int64(50))
+	})
+	t.Run("read files", func(t *testing.T) {
+		files := Walk(fsys, "d7").Filter(func(e *FileEntry) bool {
+			return !e.IsDir()
+		}).Parallel(4).Map(ReadFile).Take(math.MaxInt).Do()
+		require.Len(t, files, 10)
+		for _, f := range files {
+			require.NoError(t, f.Err)
+			require.Equal(t, f.Path[:len(f.Path)-len(".txt")], string(f.Data))
+		}
+	})
+	t.Run("open", func(t *testing.T) {
+		e := Walk(fsys, "top.txt").First()
+		f, err := e.Open()
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	})
+}
+
+func unsortedPaths(entries []FileEntry) []string {
+	paths := make([]string, len(entries))
+	for i := range entries {
+		paths[i] = entries[i].Path
+	}
+	return paths
+}
+
+// countingDirFS counts the directories read.
+type countingDirFS struct {
+	fstest.MapFS
+	reads atomic.Int64
+}
+
+func (c *countingDirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.reads.Add(1)
+	return c.MapFS.ReadDir(name)
+}
+
+// failingDirFS fails to read the directory fail.
+type failingDirFS struct {
+	fstest.MapFS
+	fail string
+}
+
+var errReadDir = errors.New("read dir failed")
+
+func (f failingDirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == f.fail {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errReadDir}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func Test_Walk_errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("directory", func(t *testing.T) {
+		fsys := failingDirFS{MapFS: walkTree(), fail: "d5"}
+		y := NewYeti()
+		var errs []error
+		y.Snag(func(err error) { errs = append(errs, err) })
+
+		res := entryPaths(Walk(fsys, ".").Parallel(4).Yeti(y).Take(math.MaxInt).Do())
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], errReadDir)
+
+		var exp []string
+		for _, p := range walkDirPaths(t, fsys.MapFS, ".") {
+			if len(p) < 3 || p[:3] != "d5/" {
+				exp = append(exp, p)
+			}
+		}
+		require.Equal(t, exp, res)
+	})
+	t.Run("root", func(t *testing.T) {
+		y := NewYeti()
+		var errs []error
+		y.Snag(func(err error) { errs = append(errs, err) })
+
+		require.Empty(t, Walk(walkTree(), "none").Yeti(y).Take(math.MaxInt).Do())
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], fs.ErrNotExist)
+	})
+}
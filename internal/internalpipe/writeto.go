@@ -0,0 +1,167 @@
+package internalpipe
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// writeChunk is the amount of elements formatted into a single buffer.
+const writeChunk = 1 << 10
+
+var writeBufPool = sync.Pool{
+	New: func() any {
+		return new([]byte)
+	},
+}
+
+// WriteTo writes the elements of the pipe formatted with format to w in the order of the pipe
+// and returns the amount of bytes written. format appends an element to the buffer and returns it.
+// The goroutines evaluating the pipe format the chunks of writeChunk elements into pooled buffers and
+// a single goroutine writes the chunks in order. At most a couple of chunks per goroutine wait to be written,
+// so the memory stays bounded whatever the length of the pipe is.
+// It stops at the first error of writing and returns it. Once the context of the pipe is done,
+// it stops and returns the error of the context.
+func WriteTo[T any](w io.Writer, p Pipe[T], format func(*T, []byte) []byte) (int64, error) {
+	return writeTo(w, p, func(x *T, b []byte) ([]byte, error) {
+		return format(x, b), nil
+	})
+}
+
+// writeTo writes the elements of the pipe the way WriteTo does, the first error of format stops it too.
+func writeTo[T any](w io.Writer, p Pipe[T], format func(*T, []byte) ([]byte, error)) (int64, error) {
+	if p.y != nil {
+		defer p.y.Handle()
+	}
+	defer p.open()()
+
+	if !p.lenSet() {
+		n, err := writeStream(w, &p, format)
+		return n, p.truncated(err)
+	}
+
+	var n int64
+	workers := max(p.GoroutinesCnt, 1)
+	seq := newSequencer(2*workers*writeChunk, func(buf *[]byte) error {
+		m, err := w.Write(*buf)
+		n += int64(m)
+		*buf = (*buf)[:0]
+		writeBufPool.Put(buf)
+		return err
+	})
+	each := p.eachIn()
+	pl := plan{workers: workers, chunk: writeChunk}
+	schedule(p.Executor(), 0, p.Len, pl, cancelable(p.ctx, func(_, lf, rg int) bool {
+		if !seq.wait(lf) {
+			return false
+		}
+		buf := writeBufPool.Get().(*[]byte)
+		var err error
+		each(lf, rg, func(x *T) bool {
+			*buf, err = format(x, *buf)
+			return err == nil
+		})
+		return seq.submit(lf, rg, buf, err)
+	}))
+	err := seq.close()
+	return n, p.truncated(err)
+}
+
+// truncated returns the error of the context of the pipe if it's done and err is nil,
+// so the output cut short by the context isn't taken for a complete one.
+func (p *Pipe[T]) truncated(err error) error {
+	if err == nil && p.canceled() {
+		return p.ctx.Err()
+	}
+	return err
+}
+
+// writeStream writes the elements of a pipe with no length formatted by the goroutines evaluating it.
+// Each chunk is formatted into a pooled buffer and the merged buffers are written in order by a single goroutine,
+// so the stream isn't held by the writer. The first error of writing or of format stops the stream.
+func writeStream[T any](w io.Writer, p *Pipe[T], format func(*T, []byte) ([]byte, error)) (int64, error) {
+	type written struct {
+		buf *[]byte
+		err error
+	}
+
+	parent := p.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	enc := p.WithContext(ctx)
+
+	var (
+		n   int64
+		err error
+	)
+	bufs := make(chan written, max(p.GoroutinesCnt, 1))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for wr := range bufs {
+			if err == nil {
+				var m int
+				m, err = w.Write(*wr.buf)
+				n += int64(m)
+				if err == nil {
+					err = wr.err
+				}
+				if err != nil {
+					cancel()
+				}
+			}
+			*wr.buf = (*wr.buf)[:0]
+			writeBufPool.Put(wr.buf)
+		}
+	}()
+
+	failed := false
+	bounded := enc.limitSet()
+	m := newStreamMerge[T](enc.limit(), false, nil)
+	fn, src := enc.Fn, enc.src
+	enc.scheduleRange(0, unboundedLimit, m.body(src, func(lf, rg int) *streamChunk[T] {
+		buf := writeBufPool.Get().(*[]byte)
+		var (
+			// ends holds the end of each element in buf if the stream is bounded, so buf is cut to the merged ones
+			ends      []int
+			formatted int
+			ferr      error
+		)
+		c := m.scan(&streamChunk[T]{lf: lf, rg: rg}, fn, src, func(_ int, obj *T) {
+			if ferr != nil {
+				return
+			}
+			b, err := format(obj, *buf)
+			if err != nil {
+				ferr = err
+				return
+			}
+			*buf = b
+			formatted++
+			if bounded {
+				ends = append(ends, len(b))
+			}
+		})
+		c.apply = func(take int) {
+			if failed {
+				return
+			}
+			if formatted >= take {
+				// the element format failed on isn't merged
+				ferr = nil
+			}
+			if ferr == nil && take < c.cnt {
+				*buf = (*buf)[:ends[take-1]]
+			}
+			failed = ferr != nil
+			bufs <- written{buf: buf, err: ferr}
+		}
+		return c
+	}))
+	close(bufs)
+	<-done
+	return n, err
+}
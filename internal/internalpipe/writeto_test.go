@@ -0,0 +1,184 @@
+package internalpipe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func appendInt(x *int, b []byte) []byte {
+	return append(strconv.AppendInt(b, int64(*x), 10), '\n')
+}
+
+func intLines(a []int) string {
+	return joinLines(a, strconv.Itoa)
+}
+
+func Test_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 100_000)
+	for i := range a {
+		a[i] = i
+	}
+
+	for _, threads := range []uint16{1, 8} {
+		var buf bytes.Buffer
+		n, err := WriteTo(&buf, Slice(a).Parallel(threads), appendInt)
+		require.NoError(t, err)
+		require.Equal(t, intLines(a), buf.String())
+		require.Equal(t, int64(buf.Len()), n)
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		p := Slice(a).Filter(func(x *int) bool { return *x%3 == 0 }).Parallel(4)
+		_, err := WriteTo(&buf, p, appendInt)
+		require.NoError(t, err)
+		require.Equal(t, intLines(Slice(a).Filter(func(x *int) bool { return *x%3 == 0 }).Do()), buf.String())
+	})
+
+	t.Run("no length", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		p := Func(func(i int) (int, bool) { return i, i%2 == 0 }).Parallel(4).Take(1000)
+		_, err := WriteTo(&buf, p, appendInt)
+		require.NoError(t, err)
+		require.Equal(t, intLines(Func(func(i int) (int, bool) { return i, i%2 == 0 }).Take(1000).Do()), buf.String())
+	})
+
+	t.Run("out of order chunks", func(t *testing.T) {
+		t.Parallel()
+
+		// the first chunks are evaluated last, they are written first by a single goroutine anyway
+		slow := func(x int) int {
+			if x < 3*writeChunk && x%64 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			return x
+		}
+		for _, p := range []Pipe[int]{
+			Slice(a).Map(slow).Parallel(8),
+			Func(func(i int) (int, bool) { return slow(i), true }).Parallel(8).Take(len(a)),
+		} {
+			w := &serialWriter{}
+			n, err := WriteTo(w, p, appendInt)
+			require.NoError(t, err)
+			require.False(t, w.overlapped.Load())
+			require.Equal(t, intLines(a), w.buf.String())
+			require.Equal(t, int64(w.buf.Len()), n)
+		}
+	})
+
+	t.Run("stages", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		p := Slice(a).Parallel(2).Map(func(x int) int { return x * 2 }).Parallel(4)
+		_, err := WriteTo(&buf, p, appendInt)
+		require.NoError(t, err)
+		require.Equal(t, intLines(Slice(a).Map(func(x int) int { return x * 2 }).Do()), buf.String())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		t.Parallel()
+
+		writeErr := errors.New("write error")
+		n, err := WriteTo(&failingWriter{n: 1000, err: writeErr}, Slice(a).Parallel(4), appendInt)
+		require.Equal(t, writeErr, err)
+		require.LessOrEqual(t, n, int64(1000))
+
+		// the endless pipe is stopped by the error
+		endless := Func(func(i int) (int, bool) { return i, true }).Parallel(4)
+		n, err = WriteTo(&failingWriter{n: 1000, err: writeErr}, endless, appendInt)
+		require.Equal(t, writeErr, err)
+		require.LessOrEqual(t, n, int64(1000))
+	})
+}
+
+// serialWriter is a bytes.Buffer noting if it's written by several goroutines at once.
+type serialWriter struct {
+	buf        bytes.Buffer
+	writing    atomic.Bool
+	overlapped atomic.Bool
+}
+
+func (w *serialWriter) Write(b []byte) (int, error) {
+	if w.writing.Swap(true) {
+		w.overlapped.Store(true)
+	}
+	defer w.writing.Store(false)
+	return w.buf.Write(b)
+}
+
+func Test_WriteTo_canceled(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = i
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, p := range []Pipe[int]{
+		Slice(a).Parallel(4).WithContext(ctx),
+		Func(func(i int) (int, bool) { return i, true }).Parallel(4).WithContext(ctx),
+	} {
+		var buf bytes.Buffer
+		n, err := WriteTo(&buf, p, appendInt)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int64(buf.Len()), n)
+		require.ErrorIs(t, WriteJSONLines(&buf, p), context.Canceled)
+		require.ErrorIs(t, WriteCSV(&buf, Derive(p, func(i int) (*sale, bool) {
+			return &sale{ID: i}, false
+		}), CSVOptions{}), context.Canceled)
+	}
+}
+
+func Test_writeTo_formatError(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = i
+	}
+	formatErr := errors.New("format error")
+
+	for _, p := range []Pipe[int]{
+		Slice(a).Parallel(4),
+		Func(func(i int) (int, bool) { return i, true }).Parallel(4).Take(len(a)),
+	} {
+		var buf bytes.Buffer
+		_, err := writeTo(&buf, p, func(x *int, b []byte) ([]byte, error) {
+			if *x == 5000 {
+				return b, formatErr
+			}
+			return appendInt(x, b), nil
+		})
+		require.Equal(t, formatErr, err)
+		// the elements before the failed one are written
+		require.Equal(t, intLines(a[:5000]), buf.String())
+	}
+
+	// the element past the limit isn't merged, so its error isn't either
+	var buf bytes.Buffer
+	p := Func(func(i int) (int, bool) { return i, i%2 == 0 }).Parallel(4).Take(1000)
+	_, err := writeTo(&buf, p, func(x *int, b []byte) ([]byte, error) {
+		if *x >= 2000 {
+			return b, formatErr
+		}
+		return appendInt(x, b), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, intLines(Func(func(i int) (int, bool) { return i, i%2 == 0 }).Take(1000).Do()), buf.String())
+}
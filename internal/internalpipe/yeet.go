@@ -72,3 +72,53 @@ type yeti interface {
 	Handle()
 	AddYeti(y yeti)
 }
+
+// relay is the Yeti of a source pipe yeeting errors on its own.
+// A terminal operation reading the source binds it to the Yeti of the evaluated pipe for the time of the evaluation,
+// so each pipe made of the source gets its own errors. The errors yeeted while it's not bound are kept
+// and passed to the next Yeti it's bound to.
+type relay struct {
+	*Yeti
+	mx sync.Mutex
+	to yeti
+}
+
+func newRelay() *relay {
+	return &relay{Yeti: NewYeti()}
+}
+
+func (r *relay) Yeet(err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.to != nil {
+		r.to.Yeet(err)
+		return
+	}
+	r.Yeti.Yeet(err)
+}
+
+// bind passes the errors yeeted so far and all the following ones to y until the returned function is called.
+// A nil y or the relay itself keeps the errors in the relay.
+func (r *relay) bind(y yeti) func() {
+	if y == nil || y == yeti(r) {
+		return noop
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.to = y
+	r.eMx.Lock()
+	errs := r.errs
+	r.errs = nil
+	r.eMx.Unlock()
+	for _, err := range errs {
+		y.Yeet(err)
+	}
+	return func() {
+		r.mx.Lock()
+		r.to = nil
+		r.mx.Unlock()
+	}
+}
@@ -92,3 +92,23 @@ func TestYeti_AddYetiHandle(t *testing.T) {
 
 	require.Equal(t, 3, handlerCalled, "Error handler is not called 3 times on 2 yeti")
 }
+
+func TestRelay_bind(t *testing.T) {
+	t.Parallel()
+
+	r := newRelay()
+	before, after, unbound := errors.New("before"), errors.New("after"), errors.New("unbound")
+	r.Yeet(before)
+
+	y := NewYeti()
+	unbind := r.bind(y)
+	r.Yeet(after)
+	unbind()
+	r.Yeet(unbound)
+
+	require.Equal(t, []error{before, after}, y.errs)
+	require.Equal(t, []error{unbound}, r.errs)
+
+	r.bind(r)()
+	require.Equal(t, []error{unbound}, r.errs)
+}
@@ -1,7 +1,10 @@
 package perf_test
 
 import (
+	"bufio"
+	"io"
 	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/koss-null/funcfrog/pkg/pipe"
@@ -59,6 +62,51 @@ func BenchmarkMapParallel(b *testing.B) {
 	}
 }
 
+func BenchmarkMapAuto(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Auto()
+		result := pipe.Map(fib).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapCheapAuto(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Auto()
+		result := pipe.Map(func(x int) int { return x + 1 }).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapCheapParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Parallel(uint16(runtime.NumCPU()))
+		result := pipe.Map(func(x int) int { return x + 1 }).Do()
+		_ = result
+	}
+}
+
 func BenchmarkMapFor(b *testing.B) {
 	b.StopTimer()
 	input := make([]int, 1_000_000)
@@ -272,3 +320,266 @@ func BenchmarkFirstFor(b *testing.B) {
 		}
 	}
 }
+
+// skewedBorder splits the input of skewed benchmarks: only the elements after it are expensive,
+// so a static split of the input leaves all the goroutines but one idle most of the time.
+const skewedBorder = 7 * 1_000_000 / 8
+
+func skewedFib(x int) int {
+	if x < skewedBorder {
+		return x
+	}
+	res := 0
+	for i := 0; i < 8; i++ {
+		res += fib(x + i)
+	}
+	return res
+}
+
+func skewedFilterFunc(x *int) bool {
+	return skewedFib(*x)%2 == 0
+}
+
+func BenchmarkMapSkewedParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Parallel(uint16(runtime.NumCPU()))
+		result := pipe.Map(skewedFib).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapSkewedFor(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := make([]int, 0, len(input))
+		for i := range input {
+			result = append(result, skewedFib(input[i]))
+		}
+		_ = result
+	}
+}
+
+func BenchmarkFilterSkewedParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Parallel(uint16(runtime.NumCPU()))
+		result := pipe.Filter(skewedFilterFunc).Do()
+		_ = result
+	}
+}
+
+func BenchmarkSumSkewedParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		pipe := pipe.Slice(input).Parallel(uint16(runtime.NumCPU()))
+		result := pipe.Map(skewedFib).Sum(func(x, y *int) int { return *x + *y })
+		_ = result
+	}
+}
+
+func BenchmarkMapAllocs(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := pipe.Slice(input).Map(func(x int) int { return x * 2 }).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapAllocsParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := pipe.Slice(input).Parallel(uint16(runtime.NumCPU())).Map(func(x int) int { return x * 2 }).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapFilterAllocs(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := pipe.Slice(input).Map(func(x int) int { return x * 2 }).Filter(filterFunc).Do()
+		_ = result
+	}
+}
+
+func BenchmarkMapAllocsFor(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := make([]int, len(input))
+		for i := range input {
+			result[i] = input[i] * 2
+		}
+		_ = result
+	}
+}
+
+func deepChain(p pipe.Piper[int]) pipe.Piper[int] {
+	return p.
+		Map(func(x int) int { return x + 1 }).
+		Filter(func(x *int) bool { return *x%3 != 0 }).
+		Map(func(x int) int { return x * 2 }).
+		Filter(func(x *int) bool { return *x%5 != 0 }).
+		Map(func(x int) int { return x - 1 })
+}
+
+func BenchmarkDeepChain(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := deepChain(pipe.Slice(input)).Do()
+		_ = result
+	}
+}
+
+func BenchmarkDeepChainParallel(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := deepChain(pipe.Slice(input).Parallel(4)).Do()
+		_ = result
+	}
+}
+
+func BenchmarkDeepChainFor(b *testing.B) {
+	b.StopTimer()
+	input := make([]int, 1_000_000)
+	for i := 0; i < len(input); i++ {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	b.StartTimer()
+
+	for j := 0; j < b.N; j++ {
+		result := make([]int, 0, len(input))
+		for _, x := range input {
+			x++
+			if x%3 == 0 {
+				continue
+			}
+			x *= 2
+			if x%5 == 0 {
+				continue
+			}
+			result = append(result, x-1)
+		}
+		_ = result
+	}
+}
+
+func rareFunc(i int) (int, bool) {
+	return fib(i) + i, true
+}
+
+func rareFilter(x *int) bool {
+	return *x%97 == 0
+}
+
+func BenchmarkTake(b *testing.B) {
+	for j := 0; j < b.N; j++ {
+		result := pipe.Func(rareFunc).Filter(rareFilter).Take(1000).Do()
+		_ = result
+	}
+}
+
+func BenchmarkTakeParallel(b *testing.B) {
+	for j := 0; j < b.N; j++ {
+		result := pipe.Func(rareFunc).Filter(rareFilter).Take(1000).Parallel(uint16(runtime.NumCPU())).Do()
+		_ = result
+	}
+}
+
+func appendLine(x *int, b []byte) []byte {
+	return append(strconv.AppendInt(b, int64(fib(*x)%1024), 10), '\n')
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	for j := 0; j < b.N; j++ {
+		_, _ = pipe.WriteTo(io.Discard, pipe.Func(func(i int) (int, bool) {
+			return i, true
+		}).Gen(1_000_000), appendLine)
+	}
+}
+
+func BenchmarkWriteToParallel(b *testing.B) {
+	for j := 0; j < b.N; j++ {
+		_, _ = pipe.WriteTo(io.Discard, pipe.Func(func(i int) (int, bool) {
+			return i, true
+		}).Gen(1_000_000).Parallel(uint16(runtime.NumCPU())), appendLine)
+	}
+}
+
+func BenchmarkWriteToFor(b *testing.B) {
+	for j := 0; j < b.N; j++ {
+		w := bufio.NewWriter(io.Discard)
+		var buf []byte
+		for i := 0; i < 1_000_000; i++ {
+			buf = appendLine(&i, buf[:0])
+			_, _ = w.Write(buf)
+		}
+		_ = w.Flush()
+	}
+}
@@ -0,0 +1,152 @@
+package pipe
+
+import (
+	"math"
+	"strings"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// Collector describes a parallel aggregation of the elements of type T into a result of type R.
+// Each goroutine accumulates its part of the pipe into its own container of type A,
+// the containers are combined and the result is made of the final one.
+type Collector[T, A, R any] interface {
+	// Supplier creates a new empty container.
+	Supplier() A
+	// Accumulate adds an element to the container and returns the container.
+	Accumulate(A, *T) A
+	// Combine merges two containers. The first one always holds the elements with lower indexes.
+	Combine(A, A) A
+	// Finish makes the result from the container.
+	Finish(A) R
+}
+
+// CollectWith evaluates the pipe aggregating it with the collector.
+// It is evaluated in parallel like Sum, so any Collector automatically benefits from Parallel().
+func CollectWith[T, A, R any](p Piper[T], c Collector[T, A, R]) R {
+	pp := any(p).(entrails[T]).Entrails()
+	return c.Finish(internalpipe.Fold(
+		*pp,
+		c.Supplier,
+		func(acc A, _ int, x *T) A { return c.Accumulate(acc, x) },
+		c.Combine,
+	))
+}
+
+type collector[T, A, R any] struct {
+	supplier   func() A
+	accumulate func(A, *T) A
+	combine    func(A, A) A
+	finish     func(A) R
+}
+
+func (c collector[T, A, R]) Supplier() A            { return c.supplier() }
+func (c collector[T, A, R]) Accumulate(a A, x *T) A { return c.accumulate(a, x) }
+func (c collector[T, A, R]) Combine(a, b A) A       { return c.combine(a, b) }
+func (c collector[T, A, R]) Finish(a A) R           { return c.finish(a) }
+
+// CollectorOf creates a Collector from the set of its functions.
+func CollectorOf[T, A, R any](
+	supplier func() A,
+	accumulate func(A, *T) A,
+	combine func(A, A) A,
+	finish func(A) R,
+) Collector[T, A, R] {
+	return collector[T, A, R]{
+		supplier:   supplier,
+		accumulate: accumulate,
+		combine:    combine,
+		finish:     finish,
+	}
+}
+
+func identity[A any](a A) A {
+	return a
+}
+
+// ToSlice returns a Collector gathering the elements into a slice in the order of the pipe.
+func ToSlice[T any]() Collector[T, []T, []T] {
+	return CollectorOf(
+		func() []T { return nil },
+		func(a []T, x *T) []T { return append(a, *x) },
+		func(a, b []T) []T { return append(a, b...) },
+		identity[[]T],
+	)
+}
+
+// Counting returns a Collector counting the elements.
+func Counting[T any]() Collector[T, int, int] {
+	return CollectorOf(
+		func() int { return 0 },
+		func(a int, _ *T) int { return a + 1 },
+		func(a, b int) int { return a + b },
+		identity[int],
+	)
+}
+
+// GroupingBy returns a Collector grouping the elements by key and aggregating each group with downstream.
+func GroupingBy[T any, K comparable, A, R any](
+	key func(*T) K,
+	downstream Collector[T, A, R],
+) Collector[T, map[K]A, map[K]R] {
+	return CollectorOf(
+		func() map[K]A { return make(map[K]A) },
+		func(m map[K]A, x *T) map[K]A {
+			k := key(x)
+			acc, ok := m[k]
+			if !ok {
+				acc = downstream.Supplier()
+			}
+			m[k] = downstream.Accumulate(acc, x)
+			return m
+		},
+		func(a, b map[K]A) map[K]A {
+			for k, acc := range b {
+				if prev, ok := a[k]; ok {
+					acc = downstream.Combine(prev, acc)
+				}
+				a[k] = acc
+			}
+			return a
+		},
+		func(m map[K]A) map[K]R {
+			res := make(map[K]R, len(m))
+			for k, acc := range m {
+				res[k] = downstream.Finish(acc)
+			}
+			return res
+		},
+	)
+}
+
+// Average is the container of Averaging: the sum and the amount of the values.
+type Average struct {
+	Sum   float64
+	Count int
+}
+
+// Averaging returns a Collector evaluating the arithmetic mean of fn(x).
+// The mean of no elements is NaN the way stats.Mean is.
+func Averaging[T any](fn func(*T) float64) Collector[T, Average, float64] {
+	return CollectorOf(
+		func() Average { return Average{} },
+		func(a Average, x *T) Average { return Average{Sum: a.Sum + fn(x), Count: a.Count + 1} },
+		func(a, b Average) Average { return Average{Sum: a.Sum + b.Sum, Count: a.Count + b.Count} },
+		func(a Average) float64 {
+			if a.Count == 0 {
+				return math.NaN()
+			}
+			return a.Sum / float64(a.Count)
+		},
+	)
+}
+
+// Joining returns a Collector concatenating the strings with sep between them.
+func Joining(sep string) Collector[string, []string, string] {
+	return CollectorOf(
+		func() []string { return nil },
+		func(a []string, s *string) []string { return append(a, *s) },
+		func(a, b []string) []string { return append(a, b...) },
+		func(a []string) string { return strings.Join(a, sep) },
+	)
+}
@@ -1,37 +1,136 @@
 package pipe
 
-import "github.com/koss-null/funcfrog/internal/internalpipe"
+import (
+	"strings"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
 
 // Collect translates Piper with erased type (achieved by calling an Erase method of any type).
 func Collect[DstT any](p Piper[any]) Piper[DstT] {
 	pp := any(p).(entrails[any]).Entrails()
-	return &Pipe[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst, ok := (*obj).(*DstT)
-				return dst, !ok
-			}
-			return nil, true
-		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	return &Pipe[DstT]{internalpipe.Derive(*pp, func(i int) (*DstT, bool) {
+		if obj, skipped := pp.Fn(i); !skipped {
+			dst, ok := (*obj).(*DstT)
+			return dst, !ok
+		}
+		return nil, true
+	})}
 }
 
 // CollectNL translates PiperNL with erased type (achieved by calling an Erase method of any type).
 func CollectNL[DstT any](p PiperNoLen[any]) PiperNoLen[DstT] {
 	pp := any(p).(entrails[any]).Entrails()
-	return &PipeNL[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst, ok := (*obj).(*DstT)
-				return dst, !ok
+	return &PipeNL[DstT]{internalpipe.Derive(*pp, func(i int) (*DstT, bool) {
+		if obj, skipped := pp.Fn(i); !skipped {
+			dst, ok := (*obj).(*DstT)
+			return dst, !ok
+		}
+		return nil, true
+	})}
+}
+
+// Conflict resolves two values with the same key: prev comes from the element with the lower index.
+type Conflict[V any] func(prev, next V) V
+
+// KeepFirst is a Conflict policy to keep the value of the element with the lowest index.
+func KeepFirst[V any](prev, _ V) V {
+	return prev
+}
+
+// KeepLast is a Conflict policy to keep the value of the element with the highest index.
+func KeepLast[V any](_, next V) V {
+	return next
+}
+
+// ToMap evaluates the pipe into a map from keyFn(x) to valFn(x).
+// The values with the same key are resolved with onConflict; nil onConflict means KeepLast.
+// Each goroutine fills its own map, the maps are merged in the order of the elements,
+// so the result does not depend on the amount of goroutines.
+func ToMap[T any, K comparable, V any](
+	p Piper[T],
+	keyFn func(*T) K,
+	valFn func(*T) V,
+	onConflict Conflict[V],
+) map[K]V {
+	if onConflict == nil {
+		onConflict = KeepLast[V]
+	}
+	put := func(m map[K]V, k K, v V) map[K]V {
+		if prev, ok := m[k]; ok {
+			v = onConflict(prev, v)
+		}
+		m[k] = v
+		return m
+	}
+
+	pp := any(p).(entrails[T]).Entrails()
+	return internalpipe.Fold(
+		*pp,
+		func() map[K]V { return make(map[K]V) },
+		func(m map[K]V, _ int, x *T) map[K]V { return put(m, keyFn(x), valFn(x)) },
+		func(a, b map[K]V) map[K]V {
+			for k, v := range b {
+				a = put(a, k, v)
+			}
+			return a
+		},
+	)
+}
+
+// Associate evaluates the pipe into a map of key-value pairs returned by fn.
+// If several elements produce the same key, the one with the highest index is kept.
+func Associate[T any, K comparable, V any](p Piper[T], fn func(*T) (K, V)) map[K]V {
+	type kv struct {
+		k K
+		v V
+	}
+	return ToMap(
+		Map(p, func(x T) kv {
+			k, v := fn(&x)
+			return kv{k, v}
+		}),
+		func(x *kv) K { return x.k },
+		func(x *kv) V { return x.v },
+		KeepLast[V],
+	)
+}
+
+// ToSet evaluates the pipe into a set of its elements.
+func ToSet[T comparable](p Piper[T]) map[T]struct{} {
+	return ToMap(
+		p,
+		func(x *T) T { return *x },
+		func(*T) struct{} { return struct{}{} },
+		KeepFirst[struct{}],
+	)
+}
+
+// Join evaluates the pipe of strings and concatenates its elements with sep between them.
+func Join(p Piper[string], sep string) string {
+	type part struct {
+		sb    *strings.Builder
+		empty bool
+	}
+	write := func(pt part, s string) part {
+		if !pt.empty {
+			pt.sb.WriteString(sep)
+		}
+		pt.sb.WriteString(s)
+		pt.empty = false
+		return pt
+	}
+
+	pp := any(p).(entrails[string]).Entrails()
+	return internalpipe.Fold(
+		*pp,
+		func() part { return part{sb: &strings.Builder{}, empty: true} },
+		func(pt part, _ int, s *string) part { return write(pt, *s) },
+		func(a, b part) part {
+			if b.empty {
+				return a
 			}
-			return nil, true
+			return write(a, b.sb.String())
 		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	).sb.String()
 }
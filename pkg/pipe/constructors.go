@@ -1,6 +1,9 @@
 package pipe
 
 import (
+	"bufio"
+	"io"
+
 	"golang.org/x/exp/constraints"
 
 	"github.com/koss-null/funcfrog/internal/internalpipe"
@@ -69,3 +72,34 @@ func Range[T constraints.Integer | constraints.Float](start, finish, step T) Pip
 func Repeat[T any](x T, n int) Piper[T] {
 	return &Pipe[T]{internalpipe.Repeat(x, n)}
 }
+
+// Lines creates a lazy sequence of the lines of r without the line endings, the length is unknown.
+// The lines are read incrementally as the pipe is evaluated, keeping only the lines being evaluated in memory,
+// so Map or Filter run in parallel while r is still being read. The read error is yeeted to the Yeti of the pipe.
+func Lines(r io.Reader) PiperNoLen[string] {
+	return Scanner(r, bufio.ScanLines)
+}
+
+// Scanner creates a lazy sequence of the tokens of r split by split the way Lines does.
+func Scanner(r io.Reader, split bufio.SplitFunc) PiperNoLen[string] {
+	return &PipeNL[string]{internalpipe.Scan(r, split)}
+}
+
+// FileLines creates a lazy sequence of the lines of the file at path IN NO PARTICULAR ORDER, the length is unknown.
+// Use FileLinesOrdered to get the lines in the order of the file.
+// The file is split into byte ranges aligned to the line boundaries read and parsed in parallel
+// by the goroutines set with Parallel on the Executor of the pipe a bounded amount of ranges ahead of it,
+// the lines of a range come once it's parsed. The file is opened by the terminal operation and closed
+// once it's over, even if it stops before the last line.
+// The errors of the file are yeeted to the Yeti of the pipe.
+func FileLines(path string) PiperNoLen[string] {
+	return &PipeNL[string]{internalpipe.FileLines(path)}
+}
+
+// FileLinesOrdered creates a lazy sequence of the lines of the file at path in the order of the file,
+// the length is unknown. The ranges of the file are read and parsed in parallel the way FileLines does
+// and their lines are given out in order.
+// The errors of the file are yeeted to the Yeti of the pipe.
+func FileLinesOrdered(path string) PiperNoLen[string] {
+	return &PipeNL[string]{internalpipe.FileLinesOrdered(path)}
+}
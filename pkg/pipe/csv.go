@@ -0,0 +1,31 @@
+package pipe
+
+import (
+	"io"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// CSVOptions sets up CSV and WriteCSV: the delimiter, comments, quoting and line endings.
+type CSVOptions = internalpipe.CSVOptions
+
+// CSV creates a lazy sequence of the structs T made of the records of r, the length is unknown.
+// The first record is the header: its columns are mapped to the exported fields of T by the `csv:"name"` tag
+// or the field name, the fields tagged with `csv:"-"` and the unknown columns are ignored.
+// The fields may be strings, bools, numbers or implement encoding.TextUnmarshaler; empty values leave them zero.
+// The records are split in order and parsed in parallel by the goroutines evaluating the pipe.
+// r is read incrementally the way Lines does.
+// A malformed record is skipped and its *LineError is yeeted to the Yeti of the pipe.
+func CSV[T any](r io.Reader, opts CSVOptions) PiperNoLen[T] {
+	return &PipeNL[T]{internalpipe.CSV[T](r, opts)}
+}
+
+// WriteCSV writes the elements of the pipe to w as CSV records keeping the order of the pipe
+// after the header made of the fields of T the way CSV maps them.
+// The records are formatted in parallel by the goroutines evaluating the pipe.
+// It stops at the first element failed to be formatted or written and returns the error,
+// the error of the context of the pipe once it's done.
+func WriteCSV[T any](w io.Writer, p Piper[T], opts CSVOptions) error {
+	pp := any(p).(entrails[T]).Entrails()
+	return internalpipe.WriteCSV(w, *pp, opts)
+}
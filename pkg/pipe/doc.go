@@ -0,0 +1,9 @@
+// Package pipe provides the lazy parallel pipes: Map, Filter, Reduce and others
+// evaluated by a set amount of goroutines over a slice, a generator or an outside source.
+//
+// The sources reading their elements from outside (Lines, Scanner, FileLines, FileLinesOrdered, JSONLines,
+// CSV, Rows, Walk, Paged and PagedCursor) are single-use: each element is read once, so only the first
+// terminal operation gets the elements, the following ones get none and yeet ErrSourceReused.
+// Their length is unknown, they end where the source does, so Take(math.MaxInt) gets all of the elements.
+// The errors of such a source are yeeted to the Yeti of the pipe being evaluated.
+package pipe
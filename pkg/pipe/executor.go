@@ -0,0 +1,31 @@
+package pipe
+
+import "github.com/koss-null/funcfrog/internal/internalpipe"
+
+// Executor is a bounded pool of goroutines shared by the pipes.
+// Each terminal operation runs one of its goroutines in the calling goroutine, the rest are taken from the pool
+// in turn with the other pipes. The parallel pipes are evaluated on DefaultExecutor() unless WithExecutor is called.
+type Executor = internalpipe.Executor
+
+// ExecutorMetrics is a snapshot of the Executor state.
+type ExecutorMetrics = internalpipe.ExecutorMetrics
+
+// DefaultExecutorSize is the amount of goroutines of the process-wide default executor.
+const DefaultExecutorSize = internalpipe.DefaultExecutorSize
+
+// NewExecutor creates an Executor running at most maxWorkers goroutines.
+// The goroutines are started on demand and live until Close is called.
+func NewExecutor(maxWorkers int) *Executor {
+	return internalpipe.NewExecutor(maxWorkers)
+}
+
+// DefaultExecutor returns the executor used by the pipes with no executor set.
+func DefaultExecutor() *Executor {
+	return internalpipe.DefaultExecutor()
+}
+
+// SetDefaultExecutor replaces the executor used by the pipes with no executor set.
+// The previous default executor is not closed. nil is ignored.
+func SetDefaultExecutor(ex *Executor) {
+	internalpipe.SetDefaultExecutor(ex)
+}
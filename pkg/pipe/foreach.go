@@ -0,0 +1,13 @@
+package pipe
+
+import "github.com/koss-null/funcfrog/internal/internalpipe"
+
+// ErrMode sets how ForEachErr treats the errors of its function.
+type ErrMode = internalpipe.ErrMode
+
+const (
+	// ContinueOnError yeets each error to the Yeti of the pipe and goes on.
+	ContinueOnError = internalpipe.ContinueOnError
+	// FailFast yeets the first error to the Yeti of the pipe and stops the evaluation.
+	FailFast = internalpipe.FailFast
+)
@@ -1,6 +1,10 @@
 package pipe
 
-import "github.com/koss-null/funcfrog/internal/internalpipe"
+import (
+	"context"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
 
 // Piper interface contains all methods of a pipe with determened length.
 type Piper[T any] interface {
@@ -11,12 +15,16 @@ type Piper[T any] interface {
 	sorter[T, Piper[T]]
 
 	paralleller[T, Piper[T]]
+	executorer[Piper[T]]
+	contexter[Piper[T]]
 
 	firster[T]
 	anier[T]
 	reducer[T]
 	summer[T]
+	minmaxer[T]
 	counter
+	foreacher[T]
 
 	promicer[T]
 	eraser[Piper[any]]
@@ -33,9 +41,12 @@ type PiperNoLen[T any] interface {
 	filterer[T, PiperNoLen[T]]
 
 	paralleller[T, PiperNoLen[T]]
+	executorer[PiperNoLen[T]]
+	contexter[PiperNoLen[T]]
 
 	firster[T]
 	anier[T]
+	foreacher[T]
 
 	eraser[PiperNoLen[any]]
 	snagger[PiperNoLen[T]]
@@ -44,6 +55,15 @@ type PiperNoLen[T any] interface {
 
 type paralleller[T, PiperT any] interface {
 	Parallel(uint16) PiperT
+	Auto() PiperT
+}
+
+type executorer[PiperT any] interface {
+	WithExecutor(*Executor) PiperT
+}
+
+type contexter[PiperT any] interface {
+	WithContext(context.Context) PiperT
 }
 
 type mapper[T, PiperT any] interface {
@@ -57,6 +77,7 @@ type filterer[T, PiperT any] interface {
 
 type sorter[T, PiperT any] interface {
 	Sort(Comparator[T]) PiperT
+	SortExternal(Comparator[T], ExternalSortOptions[T]) PiperT
 }
 
 type reducer[T any] interface {
@@ -67,6 +88,14 @@ type summer[T any] interface {
 	Sum(Accum[T]) T
 }
 
+type minmaxer[T any] interface {
+	Min(Comparator[T]) *T
+	Max(Comparator[T]) *T
+	MinMax(Comparator[T]) (*T, *T)
+	ArgMin(Comparator[T]) int
+	ArgMax(Comparator[T]) int
+}
+
 type taker[T any] interface {
 	Take(int) T
 }
@@ -79,6 +108,12 @@ type doer[T any] interface {
 	Do() []T
 }
 
+type foreacher[T any] interface {
+	ForEach(func(*T))
+	ForEachOrdered(func(*T))
+	ForEachErr(func(*T) error, ErrMode) error
+}
+
 type firster[T any] interface {
 	First() *T
 }
@@ -0,0 +1,37 @@
+package pipe
+
+import "github.com/koss-null/funcfrog/internal/internalpipe"
+
+// JoinMode sets which pairs of elements are kept by JoinBy.
+type JoinMode = internalpipe.JoinMode
+
+const (
+	// Inner keeps all pairs of left and right elements with equal keys.
+	Inner = internalpipe.Inner
+	// LeftOuter keeps all pairs of Inner and the left elements with no match paired with nil.
+	LeftOuter = internalpipe.LeftOuter
+	// Semi keeps the left elements having a match, each one once, paired with its first match.
+	Semi = internalpipe.Semi
+	// Anti keeps the left elements having no match paired with nil.
+	Anti = internalpipe.Anti
+)
+
+// JoinBy makes a hash join of two pipes by the equality of lkey and rkey results,
+// combine is applied to each kept pair of elements; the right element is nil when there is no match.
+// The right pipe is evaluated in parallel into a hash table sharded by the keys right away,
+// the left pipe stays lazy and is probed in parallel when the result is evaluated.
+// If a left element may have several matches or the left pipe has a limit but no length,
+// the result has no length and each terminal operation streams the matches of the left pipe.
+// The result keeps the order of the left pipe and the order of matches for each left element.
+func JoinBy[L, R any, K comparable, Out any](
+	left Piper[L],
+	right Piper[R],
+	lkey func(*L) K,
+	rkey func(*R) K,
+	combine func(*L, *R) Out,
+	mode JoinMode,
+) Piper[Out] {
+	lp := any(left).(entrails[L]).Entrails()
+	rp := any(right).(entrails[R]).Entrails()
+	return &Pipe[Out]{internalpipe.HashJoin(*lp, *rp, lkey, rkey, combine, mode)}
+}
@@ -0,0 +1,27 @@
+package pipe
+
+import (
+	"io"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// LineError is an error of a line of the input yeeted by a source pipe, the lines are numbered from 1.
+type LineError = internalpipe.LineError
+
+// JSONLines creates a lazy sequence of the values of T decoded from the lines of r, the length is unknown.
+// The lines are read incrementally the way Lines does and decoded in parallel by the goroutines
+// evaluating the pipe, blank lines are skipped.
+// A line failed to be decoded is skipped and its *LineError is yeeted to the Yeti of the pipe.
+func JSONLines[T any](r io.Reader) PiperNoLen[T] {
+	return &PipeNL[T]{internalpipe.JSONLines[T](r)}
+}
+
+// WriteJSONLines writes the elements of the pipe to w as JSON values one per line keeping the order of the pipe.
+// The elements are encoded in parallel by the goroutines evaluating the pipe.
+// It stops at the first element failed to be encoded or written and returns the error,
+// the error of the context of the pipe once it's done.
+func WriteJSONLines[T any](w io.Writer, p Piper[T]) error {
+	pp := any(p).(entrails[T]).Entrails()
+	return internalpipe.WriteJSONLines(w, *pp)
+}
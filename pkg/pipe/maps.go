@@ -0,0 +1,86 @@
+package pipe
+
+import (
+	"runtime"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/qsort"
+)
+
+// Entry is a key-value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// FromMap creates a Pipe of the entries of m the way Entries does, the length is known.
+func FromMap[K comparable, V any](m map[K]V) Piper[Entry[K, V]] {
+	return Entries(m)
+}
+
+// Entries creates a Pipe of the entries of m in the order of the map iteration, the length is known.
+// The entries are copied from m at the call, so m may be changed after it.
+// Use SortedEntries to get them in a reproducible order.
+func Entries[K comparable, V any](m map[K]V) Piper[Entry[K, V]] {
+	return Slice(entriesOf(m, keysOf(m)))
+}
+
+// SortedEntries creates a Pipe of the entries of m ordered by their keys with less, the length is known.
+// The entries are copied from m at the call and the keys are sorted in parallel.
+func SortedEntries[K comparable, V any](m map[K]V, less Comparator[K]) Piper[Entry[K, V]] {
+	return Slice(entriesOf(m, sortedKeysOf(m, less)))
+}
+
+// Keys creates a Pipe of the keys of m in the order of the map iteration, the length is known.
+// The keys are copied from m at the call.
+func Keys[K comparable, V any](m map[K]V) Piper[K] {
+	return Slice(keysOf(m))
+}
+
+// SortedKeys creates a Pipe of the keys of m ordered by less the way SortedEntries does, the length is known.
+func SortedKeys[K comparable, V any](m map[K]V, less Comparator[K]) Piper[K] {
+	return Slice(sortedKeysOf(m, less))
+}
+
+// Values creates a Pipe of the values of m in the order of the map iteration, the length is known.
+// The values are copied from m at the call.
+func Values[K comparable, V any](m map[K]V) Piper[V] {
+	vals := make([]V, 0, len(m))
+	for _, v := range m {
+		vals = append(vals, v)
+	}
+	return Slice(vals)
+}
+
+// SortedValues creates a Pipe of the values of m ordered by their keys with less the way SortedEntries does,
+// the length is known.
+func SortedValues[K comparable, V any](m map[K]V, less Comparator[K]) Piper[V] {
+	keys := sortedKeysOf(m, less)
+	vals := make([]V, len(keys))
+	for i, k := range keys {
+		vals[i] = m[k]
+	}
+	return Slice(vals)
+}
+
+// entriesOf returns the entries of m in the order of keys.
+func entriesOf[K comparable, V any](m map[K]V, keys []K) []Entry[K, V] {
+	entries := make([]Entry[K, V], len(keys))
+	for i, k := range keys {
+		entries[i] = Entry[K, V]{Key: k, Value: m[k]}
+	}
+	return entries
+}
+
+// keysOf returns the keys of m in the order of the map iteration.
+func keysOf[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// sortedKeysOf returns the keys of m sorted in parallel by less.
+func sortedKeysOf[K comparable, V any](m map[K]V, less Comparator[K]) []K {
+	return qsort.Sort(keysOf(m), less, runtime.GOMAXPROCS(0))
+}
@@ -0,0 +1,27 @@
+package pipe
+
+import (
+	"context"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// Paged creates a lazy sequence of the elements of the pages fetched by fetch starting with the page first,
+// the length is unknown. The pages are flattened in order and end at the first empty page.
+// The following pages are fetched concurrently ahead of the page being read on the Executor of the pipe,
+// up to 8 of them at once. fetch gets a context made of the one set with WithContext, it's canceled
+// once no more pages are needed, and the fetches ahead are waited for before the terminal operation returns.
+// The error of fetching a page ends the sequence and is yeeted to the Yeti of the pipe.
+func Paged[T any](first int, fetch func(ctx context.Context, page int) ([]T, error)) PiperNoLen[T] {
+	return &PipeNL[T]{internalpipe.Paged(first, fetch)}
+}
+
+// PagedCursor creates a lazy sequence of the elements of the pages fetched by fetch, the length is unknown.
+// fetch gets the cursor of the page and returns its elements and the cursor of the next page,
+// the first page is fetched with the empty cursor. The pages are fetched one by one as the elements run out
+// and flattened in order, the sequence ends at an empty page or after the page with the empty next cursor.
+// fetch gets the context set with WithContext or context.Background().
+// The error of fetching a page ends the sequence and is yeeted to the Yeti of the pipe.
+func PagedCursor[T any](fetch func(ctx context.Context, cursor string) ([]T, string, error)) PiperNoLen[T] {
+	return &PipeNL[T]{internalpipe.PagedCursor(fetch)}
+}
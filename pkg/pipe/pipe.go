@@ -1,6 +1,9 @@
 package pipe
 
 import (
+	"context"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/extsort"
 	"github.com/koss-null/funcfrog/internal/internalpipe"
 )
 
@@ -29,10 +32,23 @@ func (p *Pipe[T]) MapFilter(fn func(T) (T, bool)) Piper[T] {
 }
 
 // Sort sorts the underlying slice on a current step of a pipeline.
+// It's a comparison sort, the radix sorts are opt-in with SortByKey, SortInts, SortByUintKey and SortByStringKey.
 func (p *Pipe[T]) Sort(less Comparator[T]) Piper[T] {
 	return &Pipe[T]{p.Pipe.Sort(less)}
 }
 
+// SortExternal sorts the pipe like Sort does, but keeps at most opts.RunSize elements in memory at once.
+// The sorted runs are written into temporary files and merged in parallel, the result is read lazily from disk.
+// Each terminal operation sorts the pipe anew and removes the files once it's over.
+// The errors of the temporary files are yeeted to the Yeti of the pipe, the elements failed to be read are skipped.
+func (p *Pipe[T]) SortExternal(less Comparator[T], opts ExternalSortOptions[T]) Piper[T] {
+	return &Pipe[T]{p.Pipe.SortExternal(less, extsort.Options[T]{
+		Dir:     opts.Dir,
+		RunSize: opts.RunSize,
+		Codec:   opts.Codec,
+	})}
+}
+
 // Reduce applies the result of a function to each element one-by-one: f(p[n], f(p[n-1], f(p[n-2, ...]))).
 // It is recommended to use reducers from the default reducer if possible to decrease memory allocations.
 func (p *Pipe[T]) Reduce(fn Accum[T]) *T {
@@ -44,6 +60,36 @@ func (p *Pipe[T]) Sum(plus Accum[T]) T {
 	return p.Pipe.Sum(internalpipe.AccumFn[T](plus))
 }
 
+// Min returns the minimal element of the pipe or nil if the pipe is empty.
+// If there are several minimal elements, the one with the lowest index is returned.
+func (p *Pipe[T]) Min(less Comparator[T]) *T {
+	return p.Pipe.Min(less)
+}
+
+// Max returns the maximal element of the pipe or nil if the pipe is empty.
+// If there are several maximal elements, the one with the lowest index is returned.
+func (p *Pipe[T]) Max(less Comparator[T]) *T {
+	return p.Pipe.Max(less)
+}
+
+// MinMax returns both the minimal and the maximal elements of the pipe evaluating it only once.
+// Both values are nil if the pipe is empty.
+func (p *Pipe[T]) MinMax(less Comparator[T]) (*T, *T) {
+	return p.Pipe.MinMax(less)
+}
+
+// ArgMin returns the source index of the minimal element of the pipe or -1 if the pipe is empty.
+// If there are several minimal elements, the lowest index is returned.
+func (p *Pipe[T]) ArgMin(less Comparator[T]) int {
+	return p.Pipe.ArgMin(less)
+}
+
+// ArgMax returns the source index of the maximal element of the pipe or -1 if the pipe is empty.
+// If there are several maximal elements, the lowest index is returned.
+func (p *Pipe[T]) ArgMax(less Comparator[T]) int {
+	return p.Pipe.ArgMax(less)
+}
+
 // First returns the first element of the pipe.
 func (p *Pipe[T]) First() *T {
 	return p.Pipe.First()
@@ -55,7 +101,10 @@ func (p *Pipe[T]) Any() *T {
 }
 
 // Parallel set n - the amount of goroutines to run on.
-// Only the first Parallel() in a pipe chain is applied.
+// The first Parallel() in a pipe chain is applied to all the stages before and after it.
+// Parallel() called after some more stages makes a stage boundary: the following stages run on n goroutines
+// taking the elements from the previous ones through a bounded buffer.
+// Parallel() called right after another Parallel() is not applied.
 func (p *Pipe[T]) Parallel(n uint16) Piper[T] {
 	return &Pipe[T]{p.Pipe.Parallel(n)}
 }
@@ -82,6 +131,45 @@ func (p *Pipe[T]) Erase() Piper[any] {
 	return &Pipe[any]{p.Pipe.Erase()}
 }
 
+// Auto makes the pipe pick the amount of goroutines (up to GOMAXPROCS) and the chunk size by itself
+// by the cost of its first elements. Cheap pipes are evaluated in a single goroutine.
+// It is applied the same way Parallel() is.
+func (p *Pipe[T]) Auto() Piper[T] {
+	return &Pipe[T]{p.Pipe.Auto()}
+}
+
+// WithExecutor sets the executor to evaluate the pipe on instead of the default one.
+func (p *Pipe[T]) WithExecutor(ex *Executor) Piper[T] {
+	return &Pipe[T]{p.Pipe.WithExecutor(ex)}
+}
+
+// WithContext sets the context of the pipe evaluation.
+// Once ctx is done, the terminal operations stop evaluating the pipe and yeet the error of ctx
+// to the Yeti of the pipe, their result is incomplete then.
+func (p *Pipe[T]) WithContext(ctx context.Context) Piper[T] {
+	return &Pipe[T]{p.Pipe.WithContext(ctx)}
+}
+
+// ForEach calls fn for each element of the pipe in the goroutines evaluating it, the order of the calls is undefined.
+// No result is gathered. It stops once the context of the pipe is done.
+func (p *Pipe[T]) ForEach(fn func(*T)) {
+	p.Pipe.ForEach(fn)
+}
+
+// ForEachOrdered calls fn for each element of the pipe one at a time in the order of the pipe.
+// The elements are evaluated in parallel by chunks, at most a couple of chunks per goroutine wait to be passed to fn.
+// It stops once the context of the pipe is done.
+func (p *Pipe[T]) ForEachOrdered(fn func(*T)) {
+	p.Pipe.ForEachOrdered(fn)
+}
+
+// ForEachErr calls fn the way ForEach does. Each error of fn is yeeted to the Yeti of the pipe,
+// the FailFast mode stops the evaluation at the first one.
+// It returns the first error of fn or the error of the context of the pipe if it's done.
+func (p *Pipe[T]) ForEachErr(fn func(*T) error, mode ErrMode) error {
+	return p.Pipe.ForEachErr(fn, mode)
+}
+
 // Snag links an error handler to the previous Pipe method.
 func (p *Pipe[T]) Snag(h func(error)) Piper[T] {
 	return &Pipe[T]{p.Pipe.Snag(internalpipe.ErrHandler(h))}
@@ -1,12 +1,21 @@
 package pipe_test
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
 	"errors"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 
@@ -44,6 +53,291 @@ func TestCollectNL(t *testing.T) {
 	require.Equal(t, []int{0, 1, 2, 3, 5}, c)
 }
 
+func TestToMap(t *testing.T) {
+	t.Parallel()
+
+	src := pipe.Func(func(i int) (int, bool) { return i, true }).Gen(10_000)
+	key := func(x *int) int { return *x % 10 }
+	val := func(x *int) int { return *x }
+	sum := func(prev, next int) int { return prev + next }
+
+	for _, cnt := range []uint16{1, 7} {
+		p := src.Parallel(cnt)
+
+		first := pipe.ToMap(p, key, val, pipe.KeepFirst[int])
+		last := pipe.ToMap(p, key, val, pipe.KeepLast[int])
+		merged := pipe.ToMap(p, key, val, sum)
+		require.Len(t, first, 10)
+		for k := 0; k < 10; k++ {
+			require.Equal(t, k, first[k])
+			require.Equal(t, 9990+k, last[k])
+			require.Equal(t, 1000*k+4_995_000, merged[k])
+		}
+		require.Equal(t, last, pipe.ToMap(p, key, val, nil))
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	t.Parallel()
+
+	res := pipe.Associate(
+		pipe.Slice([]string{"a", "bb", "cc", "ddd"}).Parallel(3),
+		func(s *string) (int, string) { return len(*s), *s },
+	)
+	require.Equal(t, map[int]string{1: "a", 2: "cc", 3: "ddd"}, res)
+}
+
+func TestToSet(t *testing.T) {
+	t.Parallel()
+
+	res := pipe.ToSet(pipe.Cycle([]int{1, 2, 3}).Gen(1000).Parallel(4))
+	require.Equal(t, map[int]struct{}{1: {}, 2: {}, 3: {}}, res)
+	require.Empty(t, pipe.ToSet(pipe.Slice([]int{})))
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	strs := pipe.Map(pipe.Range(0, 1000, 1), strconv.Itoa)
+	exp := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		exp = append(exp, strconv.Itoa(i))
+	}
+	require.Equal(t, strings.Join(exp, ", "), pipe.Join(strs, ", "))
+	require.Equal(t, strings.Join(exp, ", "), pipe.Join(strs.Parallel(7), ", "))
+	require.Equal(t, "1-3", pipe.Join(pipe.Slice([]string{"1", "2", "3"}).Filter(pipies.NotEq("2")).Parallel(3), "-"))
+	require.Equal(t, "", pipe.Join(pipe.Slice([]string{}), "-"))
+}
+
+func TestCollectWith(t *testing.T) {
+	t.Parallel()
+
+	src := pipe.Func(func(i int) (int, bool) { return i, i%3 != 0 }).Gen(10_000)
+	exp := make([]int, 0, 10_000)
+	for i := 0; i < 10_000; i++ {
+		if i%3 != 0 {
+			exp = append(exp, i)
+		}
+	}
+
+	for _, cnt := range []uint16{1, 7} {
+		p := src.Parallel(cnt)
+
+		require.Equal(t, exp, pipe.CollectWith(p, pipe.ToSlice[int]()))
+		require.Equal(t, len(exp), pipe.CollectWith(p, pipe.Counting[int]()))
+		require.Equal(t, 4999.5, pipe.CollectWith(p, pipe.Averaging(func(x *int) float64 { return float64(*x) })))
+
+		groups := pipe.CollectWith(p, pipe.GroupingBy(func(x *int) int { return *x % 3 }, pipe.ToSlice[int]()))
+		require.Len(t, groups, 2)
+		require.Len(t, groups[1], 3333)
+		require.Equal(t, []int{2, 5, 8}, groups[2][:3])
+
+		counts := pipe.CollectWith(p, pipe.GroupingBy(func(x *int) bool { return *x < 100 }, pipe.Counting[int]()))
+		require.Equal(t, map[bool]int{true: 66, false: len(exp) - 66}, counts)
+	}
+
+	joined := pipe.CollectWith(pipe.Slice([]string{"a", "b", "c"}).Parallel(3), pipe.Joining("+"))
+	require.Equal(t, "a+b+c", joined)
+	require.True(t, math.IsNaN(pipe.CollectWith(pipe.Slice([]int{}), pipe.Averaging(func(x *int) float64 { return 1 }))))
+
+	maxLen := pipe.CollectorOf(
+		func() int { return 0 },
+		func(a int, s *string) int {
+			if len(*s) > a {
+				return len(*s)
+			}
+			return a
+		},
+		func(a, b int) int {
+			if b > a {
+				return b
+			}
+			return a
+		},
+		strconv.Itoa,
+	)
+	require.Equal(t, "3", pipe.CollectWith(pipe.Slice([]string{"a", "bbb", "cc"}), maxLen))
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var canceled error
+		p := src.Parallel(7).WithContext(ctx).Yeti(pipe.NewYeti()).Snag(func(err error) { canceled = err })
+		id := func(x *int) int { return *x }
+
+		require.Empty(t, pipe.CollectWith(p, pipe.ToSlice[int]()))
+		require.ErrorIs(t, canceled, context.Canceled)
+		require.Empty(t, pipe.ToMap(p, id, id, nil))
+		require.Empty(t, pipe.ToSet(p))
+		require.Empty(t, pipe.Associate(p, func(x *int) (int, int) { return *x, *x }))
+		require.Nil(t, p.Min(pipies.Less[int]))
+	})
+}
+
+// join
+
+func TestJoinBy(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		id   int
+		name string
+	}
+	type order struct {
+		userID int
+		item   string
+	}
+	users := pipe.Slice([]user{{1, "ann"}, {2, "bob"}, {3, "cat"}, {4, "dan"}})
+	orders := pipe.Slice([]order{{1, "apple"}, {3, "pear"}, {1, "plum"}, {5, "fig"}})
+	uid := func(u *user) int { return u.id }
+	oid := func(o *order) int { return o.userID }
+	describe := func(u *user, o *order) string {
+		if o == nil {
+			return u.name + ":-"
+		}
+		return u.name + ":" + o.item
+	}
+
+	cases := []struct {
+		mode   pipe.JoinMode
+		expect []string
+	}{
+		{pipe.Inner, []string{"ann:apple", "ann:plum", "cat:pear"}},
+		{pipe.LeftOuter, []string{"ann:apple", "ann:plum", "bob:-", "cat:pear", "dan:-"}},
+		{pipe.Semi, []string{"ann:apple", "cat:pear"}},
+		{pipe.Anti, []string{"bob:-", "dan:-"}},
+	}
+	for _, c := range cases {
+		for _, cnt := range []uint16{1, 3} {
+			res := pipe.JoinBy(users.Parallel(cnt), orders.Parallel(cnt), uid, oid, describe, c.mode)
+			require.Equal(t, c.expect, res.Do())
+			require.Equal(t, len(c.expect), res.Count())
+		}
+	}
+
+	t.Run("large", func(t *testing.T) {
+		t.Parallel()
+
+		left := pipe.Func(func(i int) (int, bool) { return i, true }).Take(10_000).Parallel(7)
+		right := pipe.Range(0, 20_000, 2).Parallel(7)
+		id := func(x *int) int { return *x }
+		res := pipe.JoinBy(left, right, id, id, func(l, r *int) int { return *l + *r }, pipe.Inner).Do()
+		require.Len(t, res, 5_000)
+		for i, x := range res {
+			require.Equal(t, 4*i, x)
+		}
+	})
+
+	t.Run("canceled build side", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var canceled error
+		canceledOrders := orders.Parallel(3).WithContext(ctx).Yeti(pipe.NewYeti()).Snag(func(err error) { canceled = err })
+		res := pipe.JoinBy(users, canceledOrders, uid, oid, describe, pipe.Anti)
+		require.ErrorIs(t, canceled, context.Canceled)
+		require.Equal(t, []string{"ann:-", "bob:-", "cat:-", "dan:-"}, res.Do())
+	})
+}
+
+// executor
+
+func TestWithExecutor(t *testing.T) {
+	t.Parallel()
+
+	ex := pipe.NewExecutor(2)
+	defer ex.Close()
+
+	p := pipe.Map(
+		pipe.Func(func(i int) (int, bool) { return i, true }).Gen(100_000).Parallel(8).WithExecutor(ex),
+		func(x int) float64 { return float64(x) },
+	)
+	require.Equal(t, float64(100_000*99_999/2), p.Sum(pipies.Sum[float64]))
+
+	m := ex.Metrics()
+	require.Equal(t, int64(7), m.Submitted)
+	require.LessOrEqual(t, m.Workers, 2)
+
+	nl := pipe.Func(func(i int) (int, bool) { return i, i%2 == 0 }).WithExecutor(ex).Parallel(4).Take(10).Do()
+	require.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, nl)
+}
+
+func TestParallelStages(t *testing.T) {
+	t.Parallel()
+
+	res := pipe.Map(
+		pipe.Range(0, 1000, 1).Parallel(2).Filter(func(x *int) bool { return *x%10 == 0 }).Parallel(16),
+		func(x int) string { return strconv.Itoa(x) },
+	).Parallel(3)
+	require.Equal(t, 100, res.Count())
+	require.Equal(t, "990", res.Do()[99])
+}
+
+func TestAuto(t *testing.T) {
+	t.Parallel()
+
+	res := pipe.Range(0, 10_000, 1).Auto().Filter(func(x *int) bool { return *x%2 == 0 }).Do()
+	require.Len(t, res, 5_000)
+	require.Equal(t, 9_998, res[len(res)-1])
+
+	first := pipe.Func(func(i int) (int, bool) { return i, true }).Auto().Filter(func(x *int) bool { return *x > 100 }).First()
+	require.Equal(t, 101, *first)
+}
+
+func TestSortExternal(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = len(a) - i
+	}
+	res := pipe.Slice(a).
+		Parallel(4).
+		SortExternal(
+			func(x, y *int) bool { return *x < *y },
+			pipe.ExternalSortOptions[int]{Dir: t.TempDir(), RunSize: 1000, Codec: pipe.GobCodec[int]()},
+		).
+		Map(func(x int) int { return x * 2 }).
+		Do()
+	require.Len(t, res, len(a))
+	for i, x := range res {
+		require.Equal(t, 2*(i+1), x)
+	}
+}
+
+func TestSortByKey(t *testing.T) {
+	t.Parallel()
+
+	a := make([]int, 10_000)
+	for i := range a {
+		a[i] = (len(a)-i)*7%1000 - 500
+	}
+	exp := make([]int, len(a))
+	copy(exp, a)
+	sort.Ints(exp)
+
+	require.Equal(t, exp, pipe.SortInts(pipe.Slice(a).Parallel(4)).Do())
+	require.Equal(t, exp, pipe.SortByKey(pipe.Slice(a), func(x *int) float64 { return float64(*x) }).Do())
+	require.Equal(t, exp, pipe.SortByKey(pipe.Slice(a), func(x *int) int16 { return int16(*x) }).Do())
+
+	type id int
+	require.Equal(t, exp, pipe.SortByKey(pipe.Slice(a), func(x *int) id { return id(*x) }).Do())
+
+	strs := pipe.Map(pipe.Slice(a), strconv.Itoa).Do()
+	sorted := pipe.SortByKey(pipe.Slice(strs).Parallel(4), func(s *string) string { return *s }).Do()
+	require.True(t, sort.StringsAreSorted(sorted))
+	require.Len(t, sorted, len(a))
+
+	digit := func(x *int) uint64 { return uint64(*x+500) % 10 }
+	byKey := pipe.SortByUintKey(pipe.Slice(a), digit).Do()
+	for i := 1; i < len(byKey); i++ {
+		require.LessOrEqual(t, digit(&byKey[i-1]), digit(&byKey[i]))
+	}
+}
+
 // testing constructions
 
 func TestSlice(t *testing.T) {
@@ -270,6 +564,260 @@ func TestRepeat(t *testing.T) {
 	}
 }
 
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	log := "INFO start\nERROR disk\nINFO ok\nERROR net\n"
+	errs := pipe.Lines(strings.NewReader(log)).
+		Filter(func(s *string) bool { return strings.HasPrefix(*s, "ERROR") }).
+		Parallel(4).
+		Take(math.MaxInt).
+		Do()
+	require.Equal(t, []string{"ERROR disk", "ERROR net"}, errs)
+
+	words := pipe.Scanner(strings.NewReader(log), bufio.ScanWords).Take(3).Do()
+	require.Equal(t, []string{"INFO", "start", "ERROR"}, words)
+
+	readErr := errors.New("read error")
+	var handled error
+	y := pipe.NewYeti()
+	pipe.Lines(iotest.ErrReader(readErr)).
+		Yeti(y).
+		Snag(func(err error) { handled = err }).
+		Take(math.MaxInt).
+		Do()
+	require.Equal(t, readErr, handled)
+}
+
+func TestFileLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "log.txt")
+	require.NoError(t, os.WriteFile(path, []byte("INFO start\nERROR disk\nINFO ok\nERROR net\n"), 0o600))
+	isErr := func(s *string) bool { return strings.HasPrefix(*s, "ERROR") }
+
+	errs := pipe.FileLines(path).Filter(isErr).Parallel(4).Take(math.MaxInt).Do()
+	sort.Strings(errs)
+	require.Equal(t, []string{"ERROR disk", "ERROR net"}, errs)
+
+	ordered := pipe.FileLinesOrdered(path).Filter(isErr).Parallel(4).Take(math.MaxInt).Do()
+	require.Equal(t, []string{"ERROR disk", "ERROR net"}, ordered)
+	require.Equal(t, []string{"INFO start", "ERROR disk"}, pipe.FileLinesOrdered(path).Take(2).Do())
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"src/main.go":     {Data: []byte("package main // TODO")},
+		"src/lib/lib.go":  {Data: []byte("package lib")},
+		"src/lib/util.go": {Data: []byte("package lib // TODO")},
+		"README.md":       {Data: []byte("TODO")},
+	}
+	todo := pipe.Walk(fsys, "src").
+		Filter(func(e *pipe.FileEntry) bool { return !e.IsDir() }).
+		Parallel(4).
+		Map(pipe.ReadFile).
+		Filter(func(e *pipe.FileEntry) bool { return e.Err == nil && strings.Contains(string(e.Data), "TODO") }).
+		Take(math.MaxInt).
+		Do()
+
+	paths := make([]string, 0, len(todo))
+	for _, e := range todo {
+		paths = append(paths, e.Path)
+	}
+	sort.Strings(paths)
+	require.Equal(t, []string{"src/lib/util.go", "src/main.go"}, paths)
+}
+
+func TestRows(t *testing.T) {
+	t.Parallel()
+
+	// the rows are read by the terminal operations only, see the tests of internalpipe.Rows for the reading
+	scanned := false
+	p := pipe.Rows(nil, func(*sql.Rows) (int, error) {
+		scanned = true
+		return 0, nil
+	}).Parallel(4).Map(func(x int) int { return x + 1 })
+	require.NotNil(t, p)
+	require.False(t, scanned)
+}
+
+func TestPaged(t *testing.T) {
+	t.Parallel()
+
+	items := []string{"a", "b", "c", "d", "e"}
+	pages := [][]string{items[:2], items[2:4], items[4:]}
+	byPage := func(_ context.Context, page int) ([]string, error) {
+		if page > len(pages) {
+			return nil, nil
+		}
+		return pages[page-1], nil
+	}
+	require.Equal(t, items, pipe.Paged(1, byPage).Parallel(4).Take(math.MaxInt).Do())
+
+	byCursor := func(_ context.Context, cursor string) ([]string, string, error) {
+		switch cursor {
+		case "":
+			return items[:3], "next", nil
+		default:
+			return items[3:], "", nil
+		}
+	}
+	require.Equal(t, items, pipe.PagedCursor(byCursor).Parallel(4).Take(math.MaxInt).Do())
+
+	errDown := errors.New("service is down")
+	var handled error
+	res := pipe.Paged(0, func(_ context.Context, page int) ([]string, error) {
+		if page == 1 {
+			return nil, errDown
+		}
+		return items, nil
+	}).Yeti(pipe.NewYeti()).Snag(func(err error) { handled = err }).Take(math.MaxInt).Do()
+	require.Equal(t, items, res)
+	require.ErrorIs(t, handled, errDown)
+}
+
+func TestFromMap(t *testing.T) {
+	t.Parallel()
+
+	m := make(map[string]int, 1000)
+	keys := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		k := "key" + strconv.Itoa(i)
+		m[k] = i
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vals := make([]int, len(keys))
+	entries := make([]pipe.Entry[string, int], len(keys))
+	for i, k := range keys {
+		vals[i] = m[k]
+		entries[i] = pipe.Entry[string, int]{Key: k, Value: m[k]}
+	}
+
+	require.Equal(t, entries, pipe.SortedEntries(m, pipies.Less[string]).Parallel(4).Do())
+	require.Equal(t, keys, pipe.SortedKeys(m, pipies.Less[string]).Do())
+	require.Equal(t, vals, pipe.SortedValues(m, pipies.Less[string]).Parallel(4).Do())
+
+	for _, p := range []pipe.Piper[pipe.Entry[string, int]]{pipe.FromMap(m), pipe.Entries(m)} {
+		unordered := p.Do()
+		sort.Slice(unordered, func(i, j int) bool { return unordered[i].Key < unordered[j].Key })
+		require.Equal(t, entries, unordered)
+	}
+	require.ElementsMatch(t, keys, pipe.Keys(m).Do())
+	require.ElementsMatch(t, vals, pipe.Values(m).Do())
+
+	big := pipe.SortedEntries(m, pipies.Less[string]).Filter(func(e *pipe.Entry[string, int]) bool {
+		return e.Value >= 998
+	}).Do()
+	require.Equal(t, []pipe.Entry[string, int]{{"key998", 998}, {"key999", 999}}, big)
+	require.Empty(t, pipe.SortedKeys(map[int]bool{}, pipies.Less[int]).Do())
+}
+
+func TestJSONLines(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		ID   int    `json:"id"`
+		Kind string `json:"kind"`
+	}
+	in := "{\"id\":1,\"kind\":\"click\"}\n{\"id\":2,\"kind\":\"view\"}\n{oops\n{\"id\":3,\"kind\":\"click\"}\n"
+
+	var handled error
+	clicks := pipe.JSONLines[event](strings.NewReader(in)).
+		Yeti(pipe.NewYeti()).
+		Snag(func(err error) { handled = err }).
+		Filter(func(e *event) bool { return e.Kind == "click" }).
+		Parallel(4).
+		Take(math.MaxInt)
+
+	var out strings.Builder
+	require.NoError(t, pipe.WriteJSONLines(&out, clicks))
+	require.Equal(t, "{\"id\":1,\"kind\":\"click\"}\n{\"id\":3,\"kind\":\"click\"}\n", out.String())
+
+	var le *pipe.LineError
+	require.True(t, errors.As(handled, &le))
+	require.Equal(t, 3, le.Line)
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name  string  `csv:"name"`
+		Score float64 `csv:"score"`
+	}
+	in := "name,score,comment\nann,4.5,ok\nbob,oops,bad\ncid,3,\n"
+
+	var handled error
+	good := pipe.CSV[row](strings.NewReader(in), pipe.CSVOptions{}).
+		Yeti(pipe.NewYeti()).
+		Snag(func(err error) { handled = err }).
+		Map(func(r row) row {
+			r.Score *= 2
+			return r
+		}).
+		Parallel(4).
+		Take(math.MaxInt)
+
+	var out strings.Builder
+	require.NoError(t, pipe.WriteCSV(&out, good, pipe.CSVOptions{Comma: ';'}))
+	require.Equal(t, "name;score\nann;9\ncid;6\n", out.String())
+
+	var le *pipe.LineError
+	require.True(t, errors.As(handled, &le))
+	require.Equal(t, 3, le.Line)
+}
+
+func TestWriteTo(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	n, err := pipe.WriteTo(&out, pipe.Range(0, 10_000, 1).Filter(func(x *int) bool { return *x%1000 == 0 }).Parallel(4),
+		func(x *int, b []byte) []byte {
+			return append(strconv.AppendInt(b, int64(*x), 10), ' ')
+		})
+	require.NoError(t, err)
+	require.Equal(t, "0 1000 2000 3000 4000 5000 6000 7000 8000 9000 ", out.String())
+	require.Equal(t, int64(out.Len()), n)
+}
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	var sum atomic.Int64
+	pipe.Range(0, 10_000, 1).Parallel(4).ForEach(func(x *int) {
+		sum.Add(int64(*x))
+	})
+	require.Equal(t, int64(10_000*9_999/2), sum.Load())
+
+	var res []int
+	pipe.Func(func(i int) (int, bool) {
+		return i, true
+	}).Parallel(4).Filter(func(x *int) bool { return *x%100 == 0 }).Take(10).ForEachOrdered(func(x *int) {
+		res = append(res, *x)
+	})
+	require.Equal(t, []int{0, 100, 200, 300, 400, 500, 600, 700, 800, 900}, res)
+
+	errTooBig := errors.New("too big")
+	err := pipe.Range(0, 10_000, 1).Parallel(4).ForEachErr(func(x *int) error {
+		if *x > 5000 {
+			return errTooBig
+		}
+		return nil
+	}, pipe.FailFast)
+	require.ErrorIs(t, err, errTooBig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var canceled error
+	pipe.Range(0, 10_000, 1).WithContext(ctx).Yeti(pipe.NewYeti()).Snag(func(err error) { canceled = err }).ForEach(func(*int) {
+		t.Fatal("the pipe with its context done is evaluated")
+	})
+	require.ErrorIs(t, canceled, context.Canceled)
+}
+
 // testing pipe and pipeNL functions
 
 func TestMap(t *testing.T) {
@@ -590,6 +1138,31 @@ func TestSum(t *testing.T) {
 	require.Equal(t, expected, res)
 }
 
+func TestMinMax(t *testing.T) {
+	t.Parallel()
+
+	p := pipe.Func(func(i int) (int, bool) {
+		return (i * 7919) % 6000, true
+	}).
+		Gen(6000).
+		Parallel(6)
+
+	require.Equal(t, 0, *p.Min(pipies.Less[int]))
+	require.Equal(t, 5999, *p.Max(pipies.Less[int]))
+	mn, mx := p.MinMax(pipies.Less[int])
+	require.Equal(t, 0, *mn)
+	require.Equal(t, 5999, *mx)
+	require.Equal(t, 0, p.ArgMin(pipies.Less[int]))
+	require.Equal(t, 5999, (p.ArgMax(pipies.Less[int])*7919)%6000)
+
+	words := pipe.Slice([]string{"bb", "a", "ccc", "d", "eee"})
+	require.Equal(t, 1, words.ArgMin(pipies.LessBy(func(s *string) int { return len(*s) })))
+	require.Equal(t, 2, words.ArgMax(pipies.LessBy(func(s *string) int { return len(*s) })))
+
+	require.Nil(t, pipe.Slice([]int{}).Min(pipies.Less[int]))
+	require.Equal(t, -1, pipe.Slice([]int{}).ArgMax(pipies.Less[int]))
+}
+
 func TestFirst(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,8 @@
 package pipe
 
 import (
+	"context"
+
 	"github.com/koss-null/funcfrog/internal/internalpipe"
 )
 
@@ -40,6 +42,8 @@ func (p *PipeNL[T]) Any() *T {
 
 // Take is used to set the amount of values expected to be in result slice.
 // It's applied only the first Gen() or Take() function in the pipe.
+// A pipe reading its elements from outside (like Lines or Rows) stops at the end of its source,
+// so the result may hold fewer values if there are not enough of them.
 func (p *PipeNL[T]) Take(n int) Piper[T] {
 	return &Pipe[T]{p.Pipe.Take(n)}
 }
@@ -51,7 +55,10 @@ func (p *PipeNL[T]) Gen(n int) Piper[T] {
 }
 
 // Parallel set n - the amount of goroutines to run on.
-// Only the first Parallel() in a pipe chain is applied.
+// The first Parallel() in a pipe chain is applied to all the stages before and after it.
+// Parallel() called after some more stages makes a stage boundary: the following stages run on n goroutines
+// taking the elements from the previous ones through a bounded buffer.
+// Parallel() called right after another Parallel() is not applied.
 func (p *PipeNL[T]) Parallel(n uint16) PiperNoLen[T] {
 	return &PipeNL[T]{p.Pipe.Parallel(n)}
 }
@@ -62,6 +69,45 @@ func (p *PipeNL[T]) Erase() PiperNoLen[any] {
 	return &PipeNL[any]{p.Pipe.Erase()}
 }
 
+// Auto makes the pipe pick the amount of goroutines (up to GOMAXPROCS) and the chunk size by itself
+// by the cost of its first elements. Cheap pipes are evaluated in a single goroutine.
+// It is applied the same way Parallel() is.
+func (p *PipeNL[T]) Auto() PiperNoLen[T] {
+	return &PipeNL[T]{p.Pipe.Auto()}
+}
+
+// WithExecutor sets the executor to evaluate the pipe on instead of the default one.
+func (p *PipeNL[T]) WithExecutor(ex *Executor) PiperNoLen[T] {
+	return &PipeNL[T]{p.Pipe.WithExecutor(ex)}
+}
+
+// WithContext sets the context of the pipe evaluation.
+// Once ctx is done, the terminal operations stop evaluating the pipe and yeet the error of ctx
+// to the Yeti of the pipe, their result is incomplete then.
+func (p *PipeNL[T]) WithContext(ctx context.Context) PiperNoLen[T] {
+	return &PipeNL[T]{p.Pipe.WithContext(ctx)}
+}
+
+// ForEach calls fn for each element of the pipe in the goroutines evaluating it, the order of the calls is undefined.
+// No result is gathered. It stops once the context of the pipe is done.
+func (p *PipeNL[T]) ForEach(fn func(*T)) {
+	p.Pipe.ForEach(fn)
+}
+
+// ForEachOrdered calls fn for each element of the pipe one at a time in the order of the pipe.
+// The elements are evaluated in parallel by chunks, at most a couple of chunks per goroutine wait to be passed to fn.
+// It stops once the context of the pipe is done.
+func (p *PipeNL[T]) ForEachOrdered(fn func(*T)) {
+	p.Pipe.ForEachOrdered(fn)
+}
+
+// ForEachErr calls fn the way ForEach does. Each error of fn is yeeted to the Yeti of the pipe,
+// the FailFast mode stops the evaluation at the first one.
+// It returns the first error of fn or the error of the context of the pipe if it's done.
+func (p *PipeNL[T]) ForEachErr(fn func(*T) error, mode ErrMode) error {
+	return p.Pipe.ForEachErr(fn, mode)
+}
+
 // Snag links an error handler to the previous Pipe method.
 func (p *PipeNL[T]) Snag(h func(error)) PiperNoLen[T] {
 	return &PipeNL[T]{p.Pipe.Snag(internalpipe.ErrHandler(h))}
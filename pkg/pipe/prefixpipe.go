@@ -12,18 +12,7 @@ func Map[SrcT any, DstT any](
 	fn func(x SrcT) DstT,
 ) Piper[DstT] {
 	pp := any(p).(entrails[SrcT]).Entrails()
-	return &Pipe[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst := fn(*obj)
-				return &dst, false
-			}
-			return nil, true
-		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	return &Pipe[DstT]{internalpipe.MapTo(*pp, fn)}
 }
 
 // MapNL applies function on a PiperNoLen of type SrcT and returns a Pipe of type DstT.
@@ -32,18 +21,7 @@ func MapNL[SrcT, DstT any](
 	fn func(x SrcT) DstT,
 ) PiperNoLen[DstT] {
 	pp := any(p).(entrails[SrcT]).Entrails()
-	return &PipeNL[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst := fn(*obj)
-				return &dst, false
-			}
-			return nil, true
-		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	return &PipeNL[DstT]{internalpipe.MapTo(*pp, fn)}
 }
 
 // MapFilter applies function on a Piper of type SrcT and returns a Pipe of type DstT.
@@ -53,18 +31,13 @@ func MapFilter[SrcT, DstT any](
 	fn func(x SrcT) (DstT, bool),
 ) Piper[DstT] {
 	pp := any(p).(entrails[SrcT]).Entrails()
-	return &Pipe[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst, exist := fn(*obj)
-				return &dst, !exist
-			}
-			return nil, true
-		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	return &Pipe[DstT]{internalpipe.Derive(*pp, func(i int) (*DstT, bool) {
+		if obj, skipped := pp.Fn(i); !skipped {
+			dst, exist := fn(*obj)
+			return &dst, !exist
+		}
+		return nil, true
+	})}
 }
 
 // MapFilterNL applies function on a PiperNoLen of type SrcT and returns a Pipe of type DstT.
@@ -74,18 +47,13 @@ func MapFilterNL[SrcT, DstT any](
 	fn func(x SrcT) (DstT, bool),
 ) PiperNoLen[DstT] {
 	pp := any(p).(entrails[SrcT]).Entrails()
-	return &PipeNL[DstT]{internalpipe.Pipe[DstT]{
-		Fn: func(i int) (*DstT, bool) {
-			if obj, skipped := pp.Fn(i); !skipped {
-				dst, exist := fn(*obj)
-				return &dst, !exist
-			}
-			return nil, true
-		},
-		Len:           pp.Len,
-		ValLim:        pp.ValLim,
-		GoroutinesCnt: pp.GoroutinesCnt,
-	}}
+	return &PipeNL[DstT]{internalpipe.Derive(*pp, func(i int) (*DstT, bool) {
+		if obj, skipped := pp.Fn(i); !skipped {
+			dst, exist := fn(*obj)
+			return &dst, !exist
+		}
+		return nil, true
+	})}
 }
 
 // Reduce applies reduce operation on Pipe of type SrcT and returns result of type DstT.
@@ -0,0 +1,18 @@
+package pipe
+
+import (
+	"database/sql"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// Rows creates a lazy sequence of the values scanned from rows by scan, the length is unknown.
+// The rows are scanned one at a time in order the way database/sql requires, only the rows of the chunks
+// being evaluated are kept, at most 4096 of them, so the following Map and Filter run in parallel
+// with a bounded look-ahead. rows are closed once the terminal operation is over, even if it stops
+// before the last row the way Take and First do.
+// A row failed to be scanned is skipped and its error is yeeted to the Yeti of the pipe
+// along with the errors of iterating rows.
+func Rows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) PiperNoLen[T] {
+	return &PipeNL[T]{internalpipe.Rows(rows, scan)}
+}
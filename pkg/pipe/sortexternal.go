@@ -0,0 +1,33 @@
+package pipe
+
+import (
+	"io"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/extsort"
+)
+
+// DefaultRunSize is the amount of elements SortExternal sorts in memory at once by default.
+const DefaultRunSize = extsort.DefaultRunSize
+
+// Codec writes and reads the elements of the temporary files of SortExternal.
+type Codec[T any] interface {
+	// NewEncoder returns a function writing the elements to w one by one.
+	NewEncoder(w io.Writer) func(*T) error
+	// NewDecoder returns a function reading the elements written by the encoder from r one by one.
+	NewDecoder(r io.Reader) func(*T) error
+}
+
+// GobCodec returns the Codec made with encoding/gob, it is used by SortExternal by default.
+func GobCodec[T any]() Codec[T] {
+	return extsort.Gob[T]{}
+}
+
+// ExternalSortOptions sets up SortExternal.
+type ExternalSortOptions[T any] struct {
+	// Dir is the directory for the temporary files, the default directory for temporary files if empty.
+	Dir string
+	// RunSize is the maximal amount of elements kept in memory to be sorted at once, DefaultRunSize if not positive.
+	RunSize int
+	// Codec writes the elements to the temporary files, GobCodec if nil.
+	Codec Codec[T]
+}
@@ -0,0 +1,72 @@
+package pipe
+
+import (
+	"golang.org/x/exp/constraints"
+
+	"github.com/koss-null/funcfrog/internal/algo/parallel/radix"
+)
+
+// SortInts sorts the pipe of integers with a parallel LSD radix sort.
+func SortInts[T constraints.Integer](p Piper[T]) Piper[T] {
+	return SortByUintKey(p, func(x *T) uint64 { return radix.IntKey(*x) })
+}
+
+// SortByUintKey sorts the pipe by the keys with a parallel LSD radix sort.
+// The key is evaluated once for each element. The sort is stable.
+func SortByUintKey[T any](p Piper[T], key func(*T) uint64) Piper[T] {
+	pp := any(p).(entrails[T]).Entrails()
+	return &Pipe[T]{pp.SortByUint(key)}
+}
+
+// SortByStringKey sorts the pipe by the keys with an MSD radix sort, the buckets are sorted in parallel.
+// The key is evaluated once for each element. The sort is stable.
+func SortByStringKey[T any](p Piper[T], key func(*T) string) Piper[T] {
+	pp := any(p).(entrails[T]).Entrails()
+	return &Pipe[T]{pp.SortByString(key)}
+}
+
+// SortByKey sorts the pipe by the keys in ascending order.
+// The keys of the builtin integer, float and string types are sorted with a radix sort
+// (floats order NaNs last and -0 before 0), the keys of other types are compared with Sort.
+// The radix sort is chosen by the type of the key here only, Sort with a comparator never uses it.
+func SortByKey[T any, K constraints.Ordered](p Piper[T], key func(*T) K) Piper[T] {
+	switch k := any(key).(type) {
+	case func(*T) int:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) int8:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) int16:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) int32:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) int64:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) uint:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) uint8:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) uint16:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) uint32:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) uint64:
+		return SortByUintKey(p, k)
+	case func(*T) uintptr:
+		return SortByUintKey(p, intKey(k))
+	case func(*T) float32:
+		return SortByUintKey(p, floatKey(k))
+	case func(*T) float64:
+		return SortByUintKey(p, floatKey(k))
+	case func(*T) string:
+		return SortByStringKey(p, k)
+	}
+	return p.Sort(func(x, y *T) bool { return key(x) < key(y) })
+}
+
+func intKey[T any, K constraints.Integer](key func(*T) K) func(*T) uint64 {
+	return func(x *T) uint64 { return radix.IntKey(key(x)) }
+}
+
+func floatKey[T any, K constraints.Float](key func(*T) K) func(*T) uint64 {
+	return func(x *T) uint64 { return radix.FloatKey(key(x)) }
+}
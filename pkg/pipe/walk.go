@@ -0,0 +1,26 @@
+package pipe
+
+import (
+	"io/fs"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// FileEntry is an entry of a file system visited by Walk: its slash-separated Path and its fs.DirEntry.
+// Its Info is read lazily, so the files are stat in parallel by a following Map or Filter.
+type FileEntry = internalpipe.FileEntry
+
+// Walk creates a lazy sequence of the entries of the file tree of fsys rooted at root, the length is unknown.
+// It visits the entries in the lexical order fs.WalkDir does, so a directory is given out before its entries.
+// The subdirectories of a listed directory are read ahead in parallel by the goroutines set with Parallel
+// on the Executor of the pipe, the walk stops once the terminal operation is over.
+// The errors of reading the directories are yeeted to the Yeti of the pipe.
+func Walk(fsys fs.FS, root string) PiperNoLen[FileEntry] {
+	return &PipeNL[FileEntry]{internalpipe.Walk(fsys, root)}
+}
+
+// ReadFile returns e with the content of its file read into Data or the error of reading it set to Err.
+// It is meant to be passed to a Map following Parallel(n), so the files are read in parallel.
+func ReadFile(e FileEntry) FileEntry {
+	return internalpipe.ReadFile(e)
+}
@@ -0,0 +1,18 @@
+package pipe
+
+import (
+	"io"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+)
+
+// WriteTo writes the elements of the pipe to w in the order of the pipe and returns the amount of bytes written.
+// format appends an element to the buffer and returns it, like the strconv.Append functions do.
+// The goroutines evaluating the pipe format the chunks of elements into pooled buffers in parallel
+// and a single goroutine writes them in order. Only a couple of chunks per goroutine are kept at once,
+// so the memory stays bounded whatever the length of the pipe is.
+// It stops at the first error of writing and returns it, the error of the context of the pipe once it's done.
+func WriteTo[T any](w io.Writer, p Piper[T], format func(*T, []byte) []byte) (int64, error) {
+	pp := any(p).(entrails[T]).Entrails()
+	return internalpipe.WriteTo(w, *pp, format)
+}
@@ -9,6 +9,10 @@ const (
 	initHandlersAmount = 5
 )
 
+// ErrSourceReused is yeeted by a terminal operation evaluating a pipe made of a single-use source read before,
+// like the one of Lines or Rows. Such a pipe gets no elements then.
+var ErrSourceReused = internalpipe.ErrSourceReused
+
 // NewYeti creates a brand new Yeti - an object for error handling.
 func NewYeti() internalpipe.YeetSnag {
 	return internalpipe.NewYeti()
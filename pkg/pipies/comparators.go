@@ -3,9 +3,22 @@ package pipies
 
 import "golang.org/x/exp/constraints"
 
-// The list of comparators to be used for Sort() method.
+// The list of comparators to be used for Sort(), Min() and Max() methods.
 
 // Less returns true if x < y, false otherwise.
 func Less[T constraints.Ordered](x, y *T) bool {
 	return *x < *y
 }
+
+// Greater returns true if x > y, false otherwise.
+// It can be used to sort in descending order.
+func Greater[T constraints.Ordered](x, y *T) bool {
+	return *x > *y
+}
+
+// LessBy returns a comparator which compares the values by the result of key function.
+func LessBy[T any, K constraints.Ordered](key func(*T) K) func(x, y *T) bool {
+	return func(x, y *T) bool {
+		return key(x) < key(y)
+	}
+}
@@ -94,6 +94,17 @@ func Test_Comparator(t *testing.T) {
 	require.True(t, Less(pointer.Ref(4.999), pointer.Ref(5.0)))
 	require.False(t, Less(pointer.Ref(float64(int(5))), pointer.Ref(5.0)))
 	require.False(t, Less(pointer.Ref(5.01), pointer.Ref(5.0)))
+
+	require.True(t, Greater(pointer.Ref(5), pointer.Ref(4)))
+	require.False(t, Greater(pointer.Ref(5), pointer.Ref(5)))
+	require.False(t, Greater(pointer.Ref("a"), pointer.Ref("b")))
+
+	byLen := LessBy(func(x *string) int { return len(*x) })
+	require.True(t, byLen(pointer.Ref("b"), pointer.Ref("aa")))
+	require.False(t, byLen(pointer.Ref("bb"), pointer.Ref("aa")))
+
+	require.Equal(t, 1, *pipe.Slice([]int{3, 1, 2}).Min(Less[int]))
+	require.Equal(t, 3, *pipe.Slice([]int{3, 1, 2}).Min(Greater[int]))
 }
 
 func Test_Accum(t *testing.T) {
@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+	"github.com/koss-null/funcfrog/pkg/pipe"
+)
+
+// Bins is a distribution of values over equal-width bins.
+// The i-th bin holds the values from [Edges[i], Edges[i+1]), the last bin also holds its right edge,
+// so there is one more edge than there are bins. The Bins of no values have neither edges nor bins.
+type Bins struct {
+	Edges  []float64
+	Counts []int
+}
+
+type bounds struct {
+	min, max float64
+	cnt      int
+}
+
+// histPart is a part of the pipe evaluated by a goroutine: its values and their bounds.
+type histPart[T Number] struct {
+	bounds
+	vals []T
+}
+
+// Histogram evaluates a histogram of the pipe elements with the given amount of bins
+// spread evenly between the minimal and the maximal element.
+// The pipe is evaluated once collecting the values along with their bounds, then the values are counted
+// in parallel the way the pipe is set up. bins less than 1 is treated as 1.
+// If all the elements are equal, there is a single bin [x, x] holding them.
+func Histogram[T Number](p pipe.Piper[T], bins int) (Bins, error) {
+	bins = internalpipe.Max(bins, 1)
+	ip, err := inner(p)
+	if err != nil {
+		return Bins{}, err
+	}
+
+	part := internalpipe.Fold(
+		ip,
+		func() histPart[T] { return histPart[T]{} },
+		func(h histPart[T], _ int, x *T) histPart[T] {
+			v := float64(*x)
+			h.vals = append(h.vals, *x)
+			if h.cnt == 0 {
+				h.bounds = bounds{min: v, max: v, cnt: 1}
+				return h
+			}
+			h.min, h.max = math.Min(h.min, v), math.Max(h.max, v)
+			h.cnt++
+			return h
+		},
+		func(a, b histPart[T]) histPart[T] {
+			switch {
+			case a.cnt == 0:
+				return b
+			case b.cnt == 0:
+				return a
+			}
+			return histPart[T]{
+				bounds: bounds{min: math.Min(a.min, b.min), max: math.Max(a.max, b.max), cnt: a.cnt + b.cnt},
+				vals:   append(a.vals, b.vals...),
+			}
+		},
+	)
+	b := part.bounds
+	switch {
+	case b.cnt == 0:
+		return Bins{}, nil
+	case b.max == b.min:
+		return Bins{Edges: []float64{b.min, b.max}, Counts: []int{b.cnt}}, nil
+	}
+
+	width := (b.max - b.min) / float64(bins)
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = b.min + width*float64(i)
+	}
+	edges[bins] = b.max
+
+	vals := internalpipe.Slice(part.vals).Parallel(uint16(ip.GoroutinesCnt)).WithExecutor(ip.Executor())
+	counts := internalpipe.Fold(
+		vals,
+		func() []int { return make([]int, bins) },
+		func(counts []int, _ int, x *T) []int {
+			bin := int((float64(*x) - b.min) / width)
+			counts[internalpipe.Min(internalpipe.Max(bin, 0), bins-1)]++
+			return counts
+		},
+		func(a, b []int) []int {
+			for i := range a {
+				a[i] += b[i]
+			}
+			return a
+		},
+	)
+	return Bins{Edges: edges, Counts: counts}, nil
+}
@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+	"github.com/koss-null/funcfrog/pkg/pipe"
+)
+
+const singleThreadSelectTreshold = 5000
+
+// Digest builds a TDigest of the pipe elements. Each goroutine builds its own digest,
+// they are merged afterwards.
+func Digest[T Number](p pipe.Piper[T], compression float64) (*TDigest, error) {
+	ip, err := inner(p)
+	if err != nil {
+		return nil, err
+	}
+	return internalpipe.Fold(
+		ip,
+		func() *TDigest { return NewTDigest(compression) },
+		func(d *TDigest, _ int, x *T) *TDigest {
+			d.Add(float64(*x))
+			return d
+		},
+		func(a, b *TDigest) *TDigest {
+			a.Merge(b)
+			return a
+		},
+	), nil
+}
+
+// ApproxQuantile returns the estimation of the q-th quantile (0 <= q <= 1) of the pipe elements made with t-digest.
+// It takes a constant amount of memory and evaluates the pipe only once.
+// It returns NaN if the pipe is empty or q is out of range.
+func ApproxQuantile[T Number](p pipe.Piper[T], q float64) (float64, error) {
+	d, err := Digest(p, DefaultCompression)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return d.Quantile(q), nil
+}
+
+// ApproxMedian returns the estimation of the median of the pipe elements made with t-digest.
+func ApproxMedian[T Number](p pipe.Piper[T]) (float64, error) {
+	return ApproxQuantile(p, 0.5)
+}
+
+// Quantile returns the exact q-th quantile (0 <= q <= 1) of the pipe elements.
+// The value is linearly interpolated between the closest ranks.
+// The result is evaluated with parallel selection, so it needs all the elements to be stored in memory.
+// It returns NaN if the pipe is empty or q is out of range.
+func Quantile[T Number](p pipe.Piper[T], q float64) (float64, error) {
+	src, err := inner(p)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if q < 0 || q > 1 {
+		return math.NaN(), nil
+	}
+	data := p.Do()
+	if len(data) == 0 {
+		return math.NaN(), nil
+	}
+
+	pos := q * float64(len(data)-1)
+	lo := int(math.Floor(pos))
+	loVal, hiVal := selectKth(data, lo, src.GoroutinesCnt, src.Executor())
+	if float64(lo) == pos {
+		return float64(loVal), nil
+	}
+	return interpolate(float64(loVal), float64(hiVal), pos-float64(lo)), nil
+}
+
+// Median returns the exact median of the pipe elements.
+func Median[T Number](p pipe.Piper[T]) (float64, error) {
+	return Quantile(p, 0.5)
+}
+
+type partition[T Number] struct {
+	lt, gt []T
+	eq     int
+}
+
+// selectKth returns the k-th smallest element of data and the one following it (the zero value if there is none).
+// Each round splits data around a pivot in parallel keeping only the part containing the k-th element.
+func selectKth[T Number](data []T, k, threads int, ex *internalpipe.Executor) (kth, next T) {
+	nextSet := false
+	for len(data) > singleThreadSelectTreshold && threads > 1 {
+		pivot := median3(data[0], data[len(data)/2], data[len(data)-1])
+		prt := internalpipe.Fold(
+			internalpipe.Slice(data).Parallel(uint16(threads)).WithExecutor(ex),
+			func() partition[T] { return partition[T]{} },
+			func(prt partition[T], _ int, x *T) partition[T] {
+				switch {
+				case *x < pivot:
+					prt.lt = append(prt.lt, *x)
+				case *x > pivot:
+					prt.gt = append(prt.gt, *x)
+				default:
+					prt.eq++
+				}
+				return prt
+			},
+			func(a, b partition[T]) partition[T] {
+				return partition[T]{
+					lt: append(a.lt, b.lt...),
+					gt: append(a.gt, b.gt...),
+					eq: a.eq + b.eq,
+				}
+			},
+		)
+
+		switch {
+		case k < len(prt.lt):
+			if k == len(prt.lt)-1 && !nextSet {
+				next, nextSet = pivot, true
+			}
+			data = prt.lt
+		case k < len(prt.lt)+prt.eq:
+			switch {
+			case k+1 < len(prt.lt)+prt.eq:
+				return pivot, pivot
+			case nextSet:
+				return pivot, next
+			}
+			for i, x := range prt.gt {
+				if i == 0 || x < next {
+					next = x
+				}
+			}
+			return pivot, next
+		default:
+			k -= len(prt.lt) + prt.eq
+			data = prt.gt
+		}
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i] < data[j] })
+	if !nextSet && k+1 < len(data) {
+		next = data[k+1]
+	}
+	return data[k], next
+}
+
+func median3[T Number](a, b, c T) T {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b = c
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
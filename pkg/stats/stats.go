@@ -0,0 +1,162 @@
+// Package stats contains numeric statistics evaluated over pipes.
+// All of the functions evaluate the pipe in parallel according to its Parallel() setting.
+// They return ErrForeignPiper for a Piper not created by the pipe package.
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/exp/constraints"
+
+	"github.com/koss-null/funcfrog/internal/internalpipe"
+	"github.com/koss-null/funcfrog/pkg/pipe"
+)
+
+// Number is a constraint for the types the statistics can be evaluated on.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// ErrForeignPiper is returned for a Piper implemented outside of the pipe package,
+// the statistics are evaluated on the internals of the pipe.
+var ErrForeignPiper = errors.New("stats: the Piper is not created by the pipe package")
+
+type entrails[T any] interface {
+	Entrails() *internalpipe.Pipe[T]
+}
+
+func inner[T any](p pipe.Piper[T]) (internalpipe.Pipe[T], error) {
+	e, ok := any(p).(entrails[T])
+	if !ok {
+		return internalpipe.Pipe[T]{}, ErrForeignPiper
+	}
+	return *e.Entrails(), nil
+}
+
+// moments holds the running mean and the sum of squared deviations (Welford's algorithm).
+type moments struct {
+	n, mean, m2 float64
+}
+
+func (m moments) add(x float64) moments {
+	m.n++
+	delta := x - m.mean
+	m.mean += delta / m.n
+	m.m2 += delta * (x - m.mean)
+	return m
+}
+
+// merge combines two moments evaluated on different parts of the data (Chan et al.).
+func (m moments) merge(o moments) moments {
+	if o.n == 0 {
+		return m
+	}
+	if m.n == 0 {
+		return o
+	}
+	n := m.n + o.n
+	delta := o.mean - m.mean
+	return moments{
+		n:    n,
+		mean: m.mean + delta*o.n/n,
+		m2:   m.m2 + o.m2 + delta*delta*m.n*o.n/n,
+	}
+}
+
+func evalMoments[T Number](p pipe.Piper[T]) (moments, error) {
+	ip, err := inner(p)
+	if err != nil {
+		return moments{}, err
+	}
+	return internalpipe.Fold(
+		ip,
+		func() moments { return moments{} },
+		func(m moments, _ int, x *T) moments { return m.add(float64(*x)) },
+		moments.merge,
+	), nil
+}
+
+// Mean returns the arithmetic mean of the pipe elements or NaN if the pipe is empty.
+func Mean[T Number](p pipe.Piper[T]) (float64, error) {
+	m, err := evalMoments(p)
+	if err != nil || m.n == 0 {
+		return math.NaN(), err
+	}
+	return m.mean, nil
+}
+
+// Variance returns the sample (unbiased) variance of the pipe elements.
+// It returns NaN if the pipe contains less than two elements.
+func Variance[T Number](p pipe.Piper[T]) (float64, error) {
+	m, err := evalMoments(p)
+	if err != nil || m.n < 2 {
+		return math.NaN(), err
+	}
+	return m.m2 / (m.n - 1), nil
+}
+
+// StdDev returns the sample standard deviation of the pipe elements.
+// It returns NaN if the pipe contains less than two elements.
+func StdDev[T Number](p pipe.Piper[T]) (float64, error) {
+	v, err := Variance(p)
+	return math.Sqrt(v), err
+}
+
+// comoments holds the running means and the sums of squared deviations and co-deviations of two variables.
+type comoments struct {
+	x, y moments
+	cxy  float64
+}
+
+func (c comoments) add(x, y float64) comoments {
+	dx := x - c.x.mean
+	c.x = c.x.add(x)
+	c.y = c.y.add(y)
+	c.cxy += dx * (y - c.y.mean)
+	return c
+}
+
+func (c comoments) merge(o comoments) comoments {
+	if o.x.n == 0 {
+		return c
+	}
+	if c.x.n == 0 {
+		return o
+	}
+	n := c.x.n + o.x.n
+	cxy := c.cxy + o.cxy + (o.x.mean-c.x.mean)*(o.y.mean-c.y.mean)*c.x.n*o.x.n/n
+	return comoments{x: c.x.merge(o.x), y: c.y.merge(o.y), cxy: cxy}
+}
+
+// Correlation returns the Pearson correlation coefficient of two pipes.
+// The elements are paired by their positions in the evaluated pipes.
+// It returns NaN if the pipes have different lengths, contain less than two elements
+// or one of them has zero variance.
+func Correlation[T Number](a, b pipe.Piper[T]) (float64, error) {
+	ip, err := inner(a)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if _, err := inner(b); err != nil {
+		return math.NaN(), err
+	}
+	xs, ys := a.Do(), b.Do()
+	if len(xs) != len(ys) || len(xs) < 2 {
+		return math.NaN(), nil
+	}
+
+	idx := internalpipe.Func(func(i int) (int, bool) { return i, true }).
+		Gen(len(xs)).
+		Parallel(uint16(ip.GoroutinesCnt))
+	c := internalpipe.Fold(
+		idx,
+		func() comoments { return comoments{} },
+		func(c comoments, _ int, i *int) comoments { return c.add(float64(xs[*i]), float64(ys[*i])) },
+		comoments.merge,
+	)
+	if c.x.m2 == 0 || c.y.m2 == 0 {
+		return math.NaN(), nil
+	}
+	return c.cxy / math.Sqrt(c.x.m2*c.y.m2), nil
+}
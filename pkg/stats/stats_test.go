@@ -0,0 +1,215 @@
+package stats_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/koss-null/funcfrog/pkg/pipe"
+	"github.com/koss-null/funcfrog/pkg/stats"
+)
+
+const delta = 1e-9
+
+// noErr returns v checking there is no error.
+func noErr[V any](t *testing.T) func(V, error) V {
+	return func(v V, err error) V {
+		t.Helper()
+		require.NoError(t, err)
+		return v
+	}
+}
+
+func TestMeanVariance(t *testing.T) {
+	t.Parallel()
+
+	f := noErr[float64](t)
+	for _, cnt := range []uint16{1, 7} {
+		p := pipe.Slice([]int{2, 4, 4, 4, 5, 5, 7, 9}).Parallel(cnt)
+		require.InDelta(t, 5., f(stats.Mean(p)), delta)
+		require.InDelta(t, 32./7, f(stats.Variance(p)), delta)
+		require.InDelta(t, math.Sqrt(32./7), f(stats.StdDev(p)), delta)
+
+		large := pipe.Range(0, 100_000, 1).Parallel(cnt)
+		require.InDelta(t, 49_999.5, f(stats.Mean(large)), delta)
+		require.InDelta(t, 833_341_666.6666666, f(stats.Variance(large)), 1e-3)
+	}
+
+	require.True(t, math.IsNaN(f(stats.Mean(pipe.Slice([]float64{})))))
+	require.True(t, math.IsNaN(f(stats.Variance(pipe.Slice([]float64{1})))))
+}
+
+func TestQuantile(t *testing.T) {
+	t.Parallel()
+
+	f := noErr[float64](t)
+	rnd := rand.New(rand.NewSource(42))
+	data := make([]float64, 100_001)
+	for i := range data {
+		data[i] = rnd.NormFloat64()
+	}
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+
+	for _, cnt := range []uint16{1, 7} {
+		p := pipe.Slice(data).Parallel(cnt)
+		require.Equal(t, sorted[50_000], f(stats.Median(p)))
+		require.Equal(t, sorted[0], f(stats.Quantile(p, 0)))
+		require.Equal(t, sorted[100_000], f(stats.Quantile(p, 1)))
+		require.InDelta(t, sorted[25_000]*0.9+sorted[25_001]*0.1, f(stats.Quantile(p, 0.250001)), 1e-6)
+
+		// the approximation error is checked in terms of rank, the digests are merged in any order
+		rank := func(x float64) float64 {
+			return float64(sort.SearchFloat64s(sorted, x)) / float64(len(sorted))
+		}
+		require.InDelta(t, 0.5, rank(f(stats.ApproxMedian(p))), 0.005)
+		require.InDelta(t, 0.01, rank(f(stats.ApproxQuantile(p, 0.01))), 0.001)
+		require.InDelta(t, 0.99, rank(f(stats.ApproxQuantile(p, 0.99))), 0.001)
+	}
+
+	require.Equal(t, 3., f(stats.Median(pipe.Slice([]int{5, 1, 3}))))
+	require.Equal(t, 2.5, f(stats.Median(pipe.Slice([]int{4, 1, 3, 2}))))
+	require.True(t, math.IsNaN(f(stats.Median(pipe.Slice([]int{})))))
+	require.True(t, math.IsNaN(f(stats.Quantile(pipe.Slice([]int{1}), 2))))
+	// q is checked before the pipe is evaluated
+	evaluated := false
+	p := pipe.Func(func(i int) (int, bool) {
+		evaluated = true
+		return i, true
+	}).Take(10)
+	require.True(t, math.IsNaN(f(stats.Quantile(p, -1))))
+	require.False(t, evaluated)
+	require.True(t, math.IsNaN(f(stats.ApproxMedian(pipe.Slice([]int{})))))
+
+	t.Run("duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		f := noErr[float64](t)
+		dups := make([]int, 20_000)
+		for i := range dups {
+			dups[i] = rnd.Intn(10) * (i % 3)
+		}
+		sorted := make([]int, len(dups))
+		copy(sorted, dups)
+		sort.Ints(sorted)
+
+		p := pipe.Slice(dups).Parallel(7)
+		for _, q := range []float64{0, 0.1, 0.33335, 0.5, 0.66666, 0.99999, 1} {
+			pos := q * float64(len(sorted)-1)
+			lo := int(math.Floor(pos))
+			exp := float64(sorted[lo])
+			if float64(lo) != pos {
+				exp += (float64(sorted[lo+1]) - exp) * (pos - float64(lo))
+			}
+			require.InDelta(t, exp, f(stats.Quantile(p, q)), delta)
+		}
+	})
+}
+
+func TestTDigest(t *testing.T) {
+	t.Parallel()
+
+	a, b := stats.NewTDigest(100), stats.NewTDigest(100)
+	for i := 0; i < 10_000; i++ {
+		a.Add(float64(i))
+		b.Add(float64(i + 10_000))
+	}
+	a.Merge(b)
+	require.Equal(t, 20_000, a.Count())
+	require.InDelta(t, 10_000, a.Quantile(0.5), 50)
+	require.InDelta(t, 0, a.Quantile(0), delta)
+	require.InDelta(t, 19_999, a.Quantile(1), delta)
+}
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	hist := noErr[stats.Bins](t)
+	for _, cnt := range []uint16{1, 7} {
+		h := hist(stats.Histogram(pipe.Range(0, 100, 1).Parallel(cnt), 3))
+		require.Equal(t, []float64{0, 33, 66, 99}, h.Edges)
+		require.Equal(t, []int{33, 33, 34}, h.Counts)
+	}
+
+	// the equal values are held by a single bin whatever their magnitude is
+	h := hist(stats.Histogram(pipe.Repeat(5, 10), 2))
+	require.Equal(t, []float64{5, 5}, h.Edges)
+	require.Equal(t, []int{10}, h.Counts)
+	h = hist(stats.Histogram(pipe.Repeat(1e300, 10).Parallel(3), 4))
+	require.Equal(t, []float64{1e300, 1e300}, h.Edges)
+	require.Equal(t, []int{10}, h.Counts)
+
+	h = hist(stats.Histogram(pipe.Slice([]int{}), 0))
+	require.Empty(t, h.Edges)
+	require.Empty(t, h.Counts)
+
+	// a single-use source is read once
+	lines := pipe.MapNL(pipe.Lines(strings.NewReader("1\n2\n3\n4\n")), func(s string) int {
+		x, _ := strconv.Atoi(s)
+		return x
+	}).Take(math.MaxInt)
+	h = hist(stats.Histogram(lines, 3))
+	require.Equal(t, []float64{1, 2, 3, 4}, h.Edges)
+	require.Equal(t, []int{1, 1, 2}, h.Counts)
+}
+
+func TestCorrelation(t *testing.T) {
+	t.Parallel()
+
+	f := noErr[float64](t)
+	x := pipe.Range(0., 10_000, 1).Parallel(7)
+	require.InDelta(t, 1., f(stats.Correlation(x, x.Map(func(x float64) float64 { return 2*x + 1 }))), delta)
+	require.InDelta(t, -1., f(stats.Correlation(x, x.Map(func(x float64) float64 { return -x }))), delta)
+	require.InDelta(t, 0.8, f(stats.Correlation(
+		pipe.Slice([]float64{1, 2, 3, 4, 5}),
+		pipe.Slice([]float64{2, 1, 4, 3, 5}),
+	)), delta)
+
+	require.True(t, math.IsNaN(f(stats.Correlation(pipe.Slice([]int{1, 2}), pipe.Slice([]int{1})))))
+	require.True(t, math.IsNaN(f(stats.Correlation(pipe.Slice([]int{1, 2}), pipe.Slice([]int{1, 1})))))
+}
+
+// foreignPiper is a Piper implemented outside of the pipe package.
+type foreignPiper struct {
+	pipe.Piper[int]
+}
+
+func TestForeignPiper(t *testing.T) {
+	t.Parallel()
+
+	p := foreignPiper{pipe.Slice([]int{1, 2, 3})}
+	_, err := stats.Mean[int](p)
+	require.ErrorIs(t, err, stats.ErrForeignPiper)
+	_, err = stats.Quantile[int](p, 0.5)
+	require.ErrorIs(t, err, stats.ErrForeignPiper)
+	_, err = stats.Histogram[int](p, 2)
+	require.ErrorIs(t, err, stats.ErrForeignPiper)
+	_, err = stats.Correlation[int](pipe.Slice([]int{1, 2, 3}), p)
+	require.ErrorIs(t, err, stats.ErrForeignPiper)
+	_, err = stats.Digest[int](p, stats.DefaultCompression)
+	require.ErrorIs(t, err, stats.ErrForeignPiper)
+}
+
+func TestCanceled(t *testing.T) {
+	t.Parallel()
+
+	f := noErr[float64](t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var canceled error
+	p := pipe.Range(0, 100_000, 1).Parallel(7).WithContext(ctx).Yeti(pipe.NewYeti()).Snag(func(err error) { canceled = err })
+
+	require.True(t, math.IsNaN(f(stats.Mean(p))))
+	require.ErrorIs(t, canceled, context.Canceled)
+	require.True(t, math.IsNaN(f(stats.Variance(p))))
+	require.True(t, math.IsNaN(f(stats.ApproxMedian(p))))
+	require.True(t, math.IsNaN(f(stats.Correlation(p, p))))
+	require.Empty(t, noErr[stats.Bins](t)(stats.Histogram(p, 3)).Edges)
+}
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultCompression is the t-digest compression used by ApproxQuantile and ApproxMedian.
+const DefaultCompression = 100
+
+type centroid struct {
+	mean, weight float64
+}
+
+// TDigest is a merging t-digest: a compact sketch of a distribution which allows
+// to estimate its quantiles with a high accuracy near the tails.
+// Digests built on different parts of data can be merged, so it is built in parallel.
+// TDigest is not safe for concurrent use.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min, max    float64
+}
+
+// NewTDigest creates an empty TDigest. The higher compression is, the more accurate
+// the estimations are and the more memory the digest takes (about 2*compression centroids).
+func NewTDigest(compression float64) *TDigest {
+	if compression < 1 {
+		compression = DefaultCompression
+	}
+	return &TDigest{
+		compression: compression,
+		buffer:      make([]centroid, 0, bufferSize(compression)),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+func bufferSize(compression float64) int {
+	return int(5 * compression)
+}
+
+// Add adds a value to the digest.
+func (d *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	d.addCentroid(centroid{mean: x, weight: 1})
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+}
+
+func (d *TDigest) addCentroid(c centroid) {
+	d.buffer = append(d.buffer, c)
+	d.count += c.weight
+	if len(d.buffer) >= bufferSize(d.compression) {
+		d.compress()
+	}
+}
+
+// Merge adds all the values from the other digest to d.
+func (d *TDigest) Merge(other *TDigest) {
+	other.compress()
+	for _, c := range other.centroids {
+		d.addCentroid(c)
+	}
+	d.min = math.Min(d.min, other.min)
+	d.max = math.Max(d.max, other.max)
+}
+
+// Count returns the amount of values added to the digest.
+func (d *TDigest) Count() int {
+	return int(d.count)
+}
+
+// k is the scale function limiting the size of centroids: they are small near the tails
+// and large in the middle of the distribution.
+func (d *TDigest) k(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (d *TDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	res := make([]centroid, 0, len(all))
+	cur := all[0]
+	weightSoFar := 0.
+	for _, c := range all[1:] {
+		q0 := weightSoFar / d.count
+		q2 := (weightSoFar + cur.weight + c.weight) / d.count
+		if d.k(q2)-d.k(q0) <= 1 {
+			cur.mean += (c.mean - cur.mean) * c.weight / (cur.weight + c.weight)
+			cur.weight += c.weight
+			continue
+		}
+		res = append(res, cur)
+		weightSoFar += cur.weight
+		cur = c
+	}
+	res = append(res, cur)
+
+	d.centroids = res
+	d.buffer = d.buffer[:0]
+}
+
+// Quantile returns the estimation of the q-th quantile (0 <= q <= 1) of the added values.
+// It returns NaN if the digest is empty or q is out of range.
+func (d *TDigest) Quantile(q float64) float64 {
+	d.compress()
+	if len(d.centroids) == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+	if len(d.centroids) == 1 {
+		return d.centroids[0].mean
+	}
+
+	target := q * d.count
+	cs := d.centroids
+	if target <= cs[0].weight/2 {
+		return interpolate(d.min, cs[0].mean, target/(cs[0].weight/2))
+	}
+
+	cumulative := cs[0].weight / 2
+	for i := 1; i < len(cs); i++ {
+		next := cumulative + (cs[i-1].weight+cs[i].weight)/2
+		if target <= next {
+			return interpolate(cs[i-1].mean, cs[i].mean, (target-cumulative)/(next-cumulative))
+		}
+		cumulative = next
+	}
+
+	last := cs[len(cs)-1]
+	return interpolate(last.mean, d.max, (target-cumulative)/(last.weight/2))
+}
+
+func interpolate(a, b, t float64) float64 {
+	return a + (b-a)*t
+}